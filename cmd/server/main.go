@@ -1,16 +1,24 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/yourusername/TouchlineTactics/internal/app/auction"
+	"github.com/yourusername/TouchlineTactics/internal/app/competition"
+	"github.com/yourusername/TouchlineTactics/internal/app/lineup"
+	"github.com/yourusername/TouchlineTactics/internal/app/match"
 	"github.com/yourusername/TouchlineTactics/internal/app/room"
+	"github.com/yourusername/TouchlineTactics/internal/app/user"
 	apphttp "github.com/yourusername/TouchlineTactics/internal/http"
 	"github.com/yourusername/TouchlineTactics/internal/storage"
 	"github.com/yourusername/TouchlineTactics/internal/ws"
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
 )
 
 func main() {
@@ -31,93 +39,132 @@ func main() {
 
 	roomService := room.NewRoomService()
 
+	go func() {
+		if err := storage.EnsurePlayerIndexes(); err != nil {
+			logger.Error("failed to ensure player indexes:", err)
+		}
+	}()
+
 	// Map of roomID to connected clients (for broadcast)
-	var roomClients = make(map[string]map[string]*ws.Client)
+	var roomClients = make(map[string]map[string]room.ClientConn)
 	var mu sync.RWMutex
 
 	// Helper: add client to room
-	addClientToRoom := func(roomID, userID string, client *ws.Client) {
+	addClientToRoom := func(roomID, userID string, client room.ClientConn) {
 		mu.Lock()
 		defer mu.Unlock()
 		if roomClients[roomID] == nil {
-			roomClients[roomID] = make(map[string]*ws.Client)
+			roomClients[roomID] = make(map[string]room.ClientConn)
 		}
 		roomClients[roomID][userID] = client
 	}
 	// Helper: remove client from all rooms
-	removeClientFromAllRooms := func(userID string) {
+	removeClientFromAllRooms := func(userID string, client room.ClientConn) {
 		mu.Lock()
 		defer mu.Unlock()
 		for _, clients := range roomClients {
-			delete(clients, userID)
+			if clients[userID] == client {
+				delete(clients, userID)
+			}
+		}
+	}
+
+	// Broadcasts are numbered per room and kept for replay on reconnect;
+	// with Redis the log is shared so clients can resume on any node
+	events := room.NewEventLog(room.DefaultEventLogSize)
+	if useRedis {
+		events.Backend = redisStore
+	}
+
+	// deliver sends a room's broadcast to the clients connected here
+	deliver := func(roomID string, msg []byte) {
+		mu.RLock()
+		defer mu.RUnlock()
+		for _, client := range roomClients[roomID] {
+			client.Send(msg)
 		}
 	}
 
-	// Broadcast function
+	// Broadcast function. With Redis every node, this one included,
+	// delivers from the pub/sub subscription below.
 	broadcast := func(roomID string, eventType room.EventType, data interface{}) {
-		msg, _ := json.Marshal(map[string]interface{}{
-			"type":    eventType,
-			"payload": data,
-		})
+		msg := events.Append(roomID, eventType, data)
 		if useRedis {
-			redisStore.PublishEvent("room:"+roomID, msg)
-		}
-		mu.RLock()
-		clients, ok := roomClients[roomID]
-		mu.RUnlock()
-		if !ok {
+			if err := redisStore.PublishEvent(context.Background(), "room:"+roomID, msg); err != nil {
+				logger.Error("failed to publish room event:", err)
+			}
 			return
 		}
-		for _, client := range clients {
-			client.Send(msg)
-		}
+		deliver(roomID, msg)
 	}
 
 	handler := &room.RoomEventHandler{
 		Store:       store,
 		RoomService: roomService,
+		UserService: user.NewUserService(),
 		Broadcast:   broadcast,
+		Lineups:     lineup.NewLineupService(),
+		Events:      events,
+	}
+	handler.Subscribe = func(roomID string, client room.ClientConn) {
+		removeClientFromAllRooms(client.ID(), client)
+		addClientToRoom(roomID, client.ID(), client)
 	}
+	handler.Unsubscribe = func(client room.ClientConn) {
+		removeClientFromAllRooms(client.ID(), client)
+	}
+	if grace, err := time.ParseDuration(os.Getenv("RECONNECT_GRACE")); err == nil {
+		handler.ReconnectGrace = grace
+	}
+	handler.InviteLinkBase = os.Getenv("INVITE_LINK_BASE")
+	// CHAT_WORDLIST names a file of words to mask in chat, one per line.
+	var blockedWords []string
+	if path := os.Getenv("CHAT_WORDLIST"); path != "" {
+		if f, err := os.Open(path); err != nil {
+			logger.Error("failed to open chat word list:", err)
+		} else {
+			if blockedWords, err = room.ReadWordList(f); err != nil {
+				logger.Error("failed to read chat word list:", err)
+			}
+			f.Close()
+		}
+	}
+	handler.ChatFilters = room.DefaultChatFilters(blockedWords)
 	dispatcher := room.NewEventDispatcher(handler)
 
 	// --- WebSocket registration logic ---
-	// Patch the WebSocket handler to update roomClients on join/leave
 	app.Get("/ws", func(c *fiber.Ctx) error {
 		userID := c.Query("userId")
 		if userID == "" {
 			return c.Status(400).SendString("Missing userId")
 		}
-		return ws.WebSocketHandlerWithRoomTracking(hub, dispatcher, userID, addClientToRoom, removeClientFromAllRooms)(c)
+		onDisconnect := func(client *ws.Client) { ws.OnDisconnect(handler, client) }
+		return ws.WebSocketHandlerWithRoomTracking(hub, dispatcher, userID, removeClientFromAllRooms, onDisconnect)(c)
 	})
 
 	// Subscribe to Redis pub/sub for distributed events
 	if useRedis {
-		redisStore.SubscribeEvents("room:*", func(msg []byte) {
-			var event map[string]interface{}
-			_ = json.Unmarshal(msg, &event)
-			roomID, _ := event["roomID"].(string)
-			mu.RLock()
-			clients, ok := roomClients[roomID]
-			mu.RUnlock()
-			if ok {
-				for _, client := range clients {
-					client.Send(msg)
-				}
-			}
+		redisStore.SubscribeEvents(context.Background(), "room:*", func(channel string, msg []byte) {
+			deliver(strings.TrimPrefix(channel, "room:"), msg)
 		})
 	}
 
 	// Register clients to roomClients map on connect
 	apphttp.SetupRoutes(app, hub, dispatcher)
 
-	// Adapt broadcast to match auction.NewAuctionService signature
-	auctionBroadcast := func(roomID string, eventType interface{}, data interface{}) {
-		broadcast(roomID, eventType.(room.EventType), data)
+	// Adapt broadcast to the auction and match service signature; they send
+	// plain string event types.
+	serviceBroadcast := func(roomID string, eventType interface{}, data interface{}) {
+		broadcast(roomID, room.EventType(fmt.Sprint(eventType)), data)
 	}
 
-	auctionService := auction.NewAuctionService(auctionBroadcast, redisStore)
+	auctionService := auction.NewAuctionService(serviceBroadcast, store)
 	auctionHandler := &auction.AuctionEventHandler{Auction: auctionService}
 	handler.AuctionHandler = auctionHandler
+	auctionService.OnComplete = handler.HandleAuctionComplete
+	auctionService.OnSold = handler.HandlePlayerSold
+	handler.Matches = match.NewMatchService(serviceBroadcast)
+	handler.Competitions = competition.NewCompetitionService(handler.Matches, serviceBroadcast)
 
 	app.Listen(":8080")
 }
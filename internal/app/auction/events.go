@@ -1,16 +1,30 @@
 package auction
 
 import (
-	"github.com/yourusername/TouchlineTactics/internal/storage"
+	"errors"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
 )
 
+// ErrBidRejected is returned when a bid is too low or over the bidder's
+// remaining budget, the bidder's squad has no room for the player, or no
+// auction is running.
+var ErrBidRejected = errors.New("bid rejected")
+
 // Event: "bidHistory"
 // Broadcasts after every bid and when a new player is up for auction.
 // Payload: { "position": string, "player": Player, "bids": [ { userId, amount, timestamp } ] }
 
 type StartAuctionPayload struct {
-	RoomID     string `json:"roomId"`
-	NumPlayers int    `json:"numPlayers"`
+	RoomID     string         `json:"roomId"`
+	NumPlayers int            `json:"numPlayers"`
+	Positions  map[string]int `json:"positions,omitempty"`
+	Profile    string         `json:"profile,omitempty"`
+	Seed       int64          `json:"seed,omitempty"`
+	Quota      SquadQuota     `json:"quota,omitempty"`
+	Budget     int            `json:"budget,omitempty"` // per manager; 0 is unlimited
+	// Pool is the room's custom player list, if the host uploaded one.
+	Pool []domain.Player `json:"-"`
 }
 
 type PlaceBidPayload struct {
@@ -24,19 +38,38 @@ type AuctionEventHandler struct {
 }
 
 func (h *AuctionEventHandler) HandleStartAuction(payload StartAuctionPayload) error {
-	players, err := storage.FetchRandomPlayers(payload.NumPlayers)
-	if err != nil {
-		return err
+	pool := NewPoolGenerator(payload.Profile, payload.Seed)
+	if len(payload.Pool) > 0 {
+		pool.UseCatalogue(payload.Pool)
 	}
-	if err2 := h.Auction.Start(payload.RoomID, players); err2 != nil {
-		if e, ok := err2.(error); ok {
-			return e
+	// Draw before announcing anything, so a pool that cannot fill the
+	// auction leaves the room as it was.
+	var positions []PositionAuction
+	if len(payload.Positions) > 0 {
+		var err error
+		if positions, err = pool.Generate(payload.Positions); err != nil {
+			return err
+		}
+	} else {
+		players, err := pool.Draw("", payload.NumPlayers)
+		if err != nil {
+			return err
 		}
-		return nil
+		positions = []PositionAuction{{Position: "ANY", Players: players}}
 	}
+	// Announce the seed so the host can replay the same pool later.
+	h.Auction.Broadcast(payload.RoomID, "auctionPool", map[string]interface{}{
+		"profile": pool.Profile.Name,
+		"seed":    pool.Seed,
+		"custom":  len(payload.Pool) > 0,
+	})
+	h.Auction.start(payload.RoomID, positions, payload.Quota, payload.Budget)
 	return nil
 }
 
-func (h *AuctionEventHandler) HandlePlaceBid(payload PlaceBidPayload) {
-	h.Auction.PlaceBid(payload.RoomID, payload.UserID, payload.Bid)
+func (h *AuctionEventHandler) HandlePlaceBid(payload PlaceBidPayload) error {
+	if !h.Auction.PlaceBid(payload.RoomID, payload.UserID, payload.Bid) {
+		return ErrBidRejected
+	}
+	return nil
 }
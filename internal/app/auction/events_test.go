@@ -0,0 +1,30 @@
+package auction
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+func TestHandleStartAuctionAnnouncesThePoolFirst(t *testing.T) {
+	var events []interface{}
+	service := NewAuctionService(func(roomID string, eventType interface{}, data interface{}) {
+		events = append(events, eventType)
+	}, nil)
+	h := &AuctionEventHandler{Auction: service}
+	var catalogue []domain.Player
+	for i := 0; i < 4; i++ {
+		catalogue = append(catalogue, player(fmt.Sprintf("p%d", i), "CM"))
+	}
+
+	err := h.HandleStartAuction(StartAuctionPayload{RoomID: "r1", NumPlayers: 2, Seed: 1, Pool: catalogue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Stop("r1")
+	if want := []interface{}{"auctionPool", "auctionPlayer", "bidHistory"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
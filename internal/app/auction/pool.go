@@ -0,0 +1,245 @@
+package auction
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/internal/storage"
+)
+
+// PoolTier is an Overall band and the share of each position's slots drawn from it.
+type PoolTier struct {
+	Name       string  `json:"name"`
+	MinOverall int     `json:"minOverall"`
+	MaxOverall int     `json:"maxOverall"`
+	Share      float64 `json:"share"`
+}
+
+type PoolProfile struct {
+	Name  string     `json:"name"`
+	Tiers []PoolTier `json:"tiers"`
+}
+
+const DefaultPoolProfile = "balanced"
+
+var PoolProfiles = map[string]PoolProfile{
+	"balanced": {Name: "balanced", Tiers: []PoolTier{
+		{Name: "elite", MinOverall: 85, MaxOverall: 99, Share: 0.2},
+		{Name: "mid", MinOverall: 75, MaxOverall: 84, Share: 0.5},
+		{Name: "budget", MinOverall: 0, MaxOverall: 74, Share: 0.3},
+	}},
+	"stars": {Name: "stars", Tiers: []PoolTier{
+		{Name: "elite", MinOverall: 85, MaxOverall: 99, Share: 0.5},
+		{Name: "mid", MinOverall: 78, MaxOverall: 84, Share: 0.5},
+	}},
+	"budget": {Name: "budget", Tiers: []PoolTier{
+		{Name: "mid", MinOverall: 70, MaxOverall: 79, Share: 0.3},
+		{Name: "budget", MinOverall: 0, MaxOverall: 69, Share: 0.7},
+	}},
+	"random": {Name: "random", Tiers: []PoolTier{
+		{Name: "any", MinOverall: 0, MaxOverall: 99, Share: 1},
+	}},
+}
+
+// PoolGenerator draws stratified, duplicate-free player pools. Two generators
+// built with the same profile and non-zero seed produce the same pool from
+// the same catalogue.
+type PoolGenerator struct {
+	Profile PoolProfile
+	Seed    int64
+	rng     *rand.Rand
+	used    map[string]bool
+	// count sizes a band of the catalogue and fetch reads the players at
+	// the given offsets of it, in a fixed order.
+	count func(position string, minOverall, maxOverall int) (int, error)
+	fetch func(position string, minOverall, maxOverall int, offsets []int) ([]domain.Player, error)
+}
+
+func NewPoolGenerator(profile string, seed int64) *PoolGenerator {
+	p, ok := PoolProfiles[profile]
+	if !ok {
+		p = PoolProfiles[DefaultPoolProfile]
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &PoolGenerator{
+		Profile: p,
+		Seed:    seed,
+		rng:     rand.New(rand.NewSource(seed)),
+		used:    make(map[string]bool),
+		count:   storage.CountPlayersByOverall,
+		fetch:   storage.FetchPlayersByOverall,
+	}
+}
+
+// UseCatalogue makes the generator draw from a fixed player list, such as a
+// host-uploaded pool, instead of the global player collection.
+func (g *PoolGenerator) UseCatalogue(players []domain.Player) {
+	catalogue := append([]domain.Player{}, players...)
+	sort.SliceStable(catalogue, func(i, j int) bool { return catalogue[i].ID < catalogue[j].ID })
+	band := func(position string, minOverall, maxOverall int) []domain.Player {
+		var matches []domain.Player
+		for _, p := range catalogue {
+			if p.Overall < minOverall || p.Overall > maxOverall {
+				continue
+			}
+			if position != "" && !p.CanPlay(position) {
+				continue
+			}
+			matches = append(matches, p)
+		}
+		return matches
+	}
+	g.count = func(position string, minOverall, maxOverall int) (int, error) {
+		return len(band(position, minOverall, maxOverall)), nil
+	}
+	g.fetch = func(position string, minOverall, maxOverall int, offsets []int) ([]domain.Player, error) {
+		matches := band(position, minOverall, maxOverall)
+		var players []domain.Player
+		for _, i := range offsets {
+			if i < len(matches) {
+				players = append(players, matches[i])
+			}
+		}
+		return players, nil
+	}
+}
+
+// Generate builds one PositionAuction per position, in sorted position order.
+func (g *PoolGenerator) Generate(posMap map[string]int) ([]PositionAuction, error) {
+	positions := make([]string, 0, len(posMap))
+	for pos := range posMap {
+		positions = append(positions, pos)
+	}
+	sort.Strings(positions)
+
+	var auctions []PositionAuction
+	for _, pos := range positions {
+		players, err := g.Draw(pos, posMap[pos])
+		if err != nil {
+			return nil, err
+		}
+		auctions = append(auctions, PositionAuction{Position: pos, Players: players})
+	}
+	return auctions, nil
+}
+
+// Draw picks n players for a position, splitting them across the profile's
+// tiers. Shortfalls in one tier are backfilled from players of any rating.
+func (g *PoolGenerator) Draw(position string, n int) ([]domain.Player, error) {
+	counts := tierCounts(g.Profile.Tiers, n)
+	var picked []domain.Player
+	for i, tier := range g.Profile.Tiers {
+		players, err := g.sample(position, tier.MinOverall, tier.MaxOverall, counts[i])
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, players...)
+	}
+	if len(picked) < n {
+		// Catalogues that don't cover the profile's bands (e.g. a custom pool
+		// of lower-rated players) fall back to any rating for the position.
+		rest, err := g.sample(position, 0, 99, n-len(picked))
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, rest...)
+	}
+	g.rng.Shuffle(len(picked), func(a, b int) {
+		picked[a], picked[b] = picked[b], picked[a]
+	})
+	return picked, nil
+}
+
+// sample draws up to n unused players from a band at seeded offsets, so
+// only the players drawn are fetched. Offsets holding players already in
+// the pool are replaced by fresh ones until the band runs out.
+func (g *PoolGenerator) sample(position string, minOverall, maxOverall, n int) ([]domain.Player, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	total, err := g.count(position, minOverall, maxOverall)
+	if err != nil {
+		return nil, err
+	}
+	var picked []domain.Player
+	tried := make(map[int]bool)
+	for len(picked) < n && len(tried) < total {
+		offsets := g.offsets(total, n-len(picked), tried)
+		players, err := g.fetch(position, minOverall, maxOverall, offsets)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range players {
+			if len(picked) < n && !g.used[playerKey(p)] {
+				g.used[playerKey(p)] = true
+				picked = append(picked, p)
+			}
+		}
+	}
+	return picked, nil
+}
+
+// offsets picks up to n offsets below total that are not yet in tried, and
+// adds them to it.
+func (g *PoolGenerator) offsets(total, n int, tried map[int]bool) []int {
+	var offsets []int
+	if left := total - len(tried); left <= n {
+		// Few enough remain to take them all.
+		for i := 0; i < total; i++ {
+			if !tried[i] {
+				tried[i] = true
+				offsets = append(offsets, i)
+			}
+		}
+		return offsets
+	}
+	for len(offsets) < n {
+		i := g.rng.Intn(total)
+		if !tried[i] {
+			tried[i] = true
+			offsets = append(offsets, i)
+		}
+	}
+	return offsets
+}
+
+// tierCounts splits n across tiers by share using the largest remainder method.
+func tierCounts(tiers []PoolTier, n int) []int {
+	counts := make([]int, len(tiers))
+	remainders := make([]float64, len(tiers))
+	total := 0.0
+	for _, t := range tiers {
+		total += t.Share
+	}
+	if total == 0 {
+		return counts
+	}
+	assigned := 0
+	for i, t := range tiers {
+		exact := float64(n) * t.Share / total
+		counts[i] = int(exact)
+		remainders[i] = exact - float64(counts[i])
+		assigned += counts[i]
+	}
+	order := make([]int, len(tiers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for i := 0; assigned < n; i++ {
+		counts[order[i%len(order)]]++
+		assigned++
+	}
+	return counts
+}
+
+func playerKey(p domain.Player) string {
+	if p.ID != "" {
+		return p.ID
+	}
+	return p.Name + "|" + p.Club
+}
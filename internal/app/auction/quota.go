@@ -0,0 +1,79 @@
+package auction
+
+import "github.com/yourusername/TouchlineTactics/internal/domain"
+
+// SquadQuota caps how many players of each line a manager may buy. Lines
+// without an entry are unlimited.
+type SquadQuota map[domain.PositionLine]int
+
+// CanAdd reports whether p can join squad without breaking the quota. Players
+// with several eligible lines are placed wherever there is room, so the check
+// is a bipartite matching of players onto line slots.
+func (q SquadQuota) CanAdd(squad []domain.Player, p domain.Player) bool {
+	if len(q) == 0 {
+		return true
+	}
+	players := append(append([]domain.Player{}, squad...), p)
+	used := make(map[domain.PositionLine]int)
+	assigned := make(map[domain.PositionLine][]int)
+	for i := range players {
+		if !q.place(players, i, used, assigned, make(map[domain.PositionLine]bool)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Full reports whether the squad has taken every slot of a quota that
+// limits all lines, so the manager cannot buy anyone else.
+func (q SquadQuota) Full(squad []domain.Player) bool {
+	total := 0
+	for _, line := range domain.Lines {
+		max, limited := q[line]
+		if !limited {
+			return false
+		}
+		total += max
+	}
+	return len(squad) >= total
+}
+
+// place tries to seat players[i] in one of its lines, moving previously
+// seated players to other lines when needed.
+func (q SquadQuota) place(players []domain.Player, i int, used map[domain.PositionLine]int, assigned map[domain.PositionLine][]int, visited map[domain.PositionLine]bool) bool {
+	lines := players[i].PlayerLines()
+	if len(lines) == 0 {
+		return true // unknown positions are not subject to quotas
+	}
+	for _, line := range lines {
+		max, limited := q[line]
+		if !limited {
+			return true
+		}
+		if visited[line] {
+			continue
+		}
+		visited[line] = true
+		if used[line] < max {
+			used[line]++
+			assigned[line] = append(assigned[line], i)
+			return true
+		}
+		for k, j := range assigned[line] {
+			if j == i {
+				continue
+			}
+			// Temporarily free j's seat and try to move j elsewhere.
+			used[line]--
+			assigned[line] = append(assigned[line][:k:k], assigned[line][k+1:]...)
+			if q.place(players, j, used, assigned, visited) {
+				used[line]++
+				assigned[line] = append(assigned[line], i)
+				return true
+			}
+			used[line]++
+			assigned[line] = append(assigned[line][:k:k], append([]int{j}, assigned[line][k:]...)...)
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package auction
+
+import (
+	"testing"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+func player(name, position string, eligible ...string) domain.Player {
+	return domain.Player{ID: name, Name: name, Position: position, EligiblePositions: eligible}
+}
+
+func TestSquadQuotaCanAdd(t *testing.T) {
+	oneEach := SquadQuota{domain.LineDefence: 1, domain.LineMidfield: 1, domain.LineForward: 1}
+	tests := []struct {
+		name  string
+		quota SquadQuota
+		squad []domain.Player
+		add   domain.Player
+		want  bool
+	}{
+		{"no quota", nil, []domain.Player{player("a", "CB"), player("b", "CB")}, player("c", "CB"), true},
+		{"room in the line", oneEach, []domain.Player{player("a", "CB")}, player("b", "CM"), true},
+		{"line full", oneEach, []domain.Player{player("a", "CM")}, player("b", "CM"), false},
+		{"unlimited line", SquadQuota{domain.LineDefence: 1}, []domain.Player{player("a", "CM")}, player("b", "CM"), true},
+		{"unknown position", oneEach, []domain.Player{player("a", "CM")}, player("b", "??"), true},
+		{"new player has a second line", oneEach, []domain.Player{player("a", "CM")}, player("b", "CM", "CB"), true},
+		// Greedy seating put a in midfield; moving a back to defence frees
+		// the seat for b.
+		{"move a seated player", oneEach, []domain.Player{player("a", "CM", "CB")}, player("b", "CM"), true},
+		// c needs forward; a moves to midfield, which moves b to defence.
+		{"move a chain of players", oneEach, []domain.Player{player("a", "ST", "CM"), player("b", "CM", "CB")}, player("c", "ST"), true},
+		{"chain ends in a full line", oneEach, []domain.Player{player("a", "ST", "CM"), player("b", "CM", "CB"), player("d", "CB")}, player("c", "ST"), false},
+		{"more players than seats", SquadQuota{domain.LineDefence: 1, domain.LineMidfield: 1}, []domain.Player{player("a", "CM", "CB"), player("b", "CB", "CM")}, player("c", "CM", "CB"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quota.CanAdd(tt.squad, tt.add); got != tt.want {
+				t.Errorf("CanAdd() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSquadQuotaFull(t *testing.T) {
+	all := SquadQuota{domain.LineGoalkeeper: 1, domain.LineDefence: 1, domain.LineMidfield: 1, domain.LineForward: 1}
+	squad := []domain.Player{player("g", "GK"), player("d", "CB"), player("m", "CM")}
+	if all.Full(squad) {
+		t.Error("three of four seats reported full")
+	}
+	if !all.Full(append(squad, player("f", "ST"))) {
+		t.Error("four of four seats not reported full")
+	}
+	partial := SquadQuota{domain.LineDefence: 1}
+	if partial.Full(append(squad, player("f", "ST"))) {
+		t.Error("a quota with unlimited lines reported full")
+	}
+}
@@ -1,13 +1,20 @@
 package auction
 
 import (
+	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/yourusername/TouchlineTactics/internal/app/lineup"
 	"github.com/yourusername/TouchlineTactics/internal/domain"
-	"github.com/yourusername/TouchlineTactics/internal/storage"
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
 )
 
+// BidWindow is how long each player stays up for auction.
+const BidWindow = 10 * time.Second
+
 type PositionAuction struct {
 	Position string
 	Players  []domain.Player
@@ -28,66 +35,101 @@ type AuctionState struct {
 	Timer         *time.Timer
 	Mutex         sync.Mutex
 	BidHistory    []Bid // Bid history for the current player
+	Quota         SquadQuota
+	Squads        map[string][]domain.Player // userID -> players bought
+	Budget        int                        // per manager; 0 is unlimited
+	Spent         map[string]int             // userID -> total of winning bids
+	Paused        bool
+}
+
+// TeamStore persists the players each manager wins.
+type TeamStore interface {
+	AddPlayerToTeam(ctx context.Context, roomID, userID string, player domain.Player) error
 }
 
 type AuctionService struct {
 	State      map[string]*AuctionState // roomID -> state
 	StateMutex sync.Mutex
 	Broadcast  func(roomID string, eventType interface{}, data interface{})
-	Redis      *storage.RedisStore
+	Teams      TeamStore
+	Lineups    *lineup.LineupService
+	// OnComplete is called once every player has been auctioned.
+	OnComplete func(roomID string)
+	// OnSold is called after each player's bid window closes.
+	OnSold func(roomID string, sale Sale)
 }
 
-func (a *AuctionService) Start(d string, players []domain.Player) any {
-	panic("unimplemented")
+// Sale is the outcome of one player's bid window. Winner is empty if nobody
+// bid; SquadFull reports that the winner's quota is now filled and
+// BudgetExhausted that they have nothing left to spend.
+type Sale struct {
+	Player          domain.Player `json:"player"`
+	Winner          string        `json:"winner,omitempty"`
+	Bid             int           `json:"bid"`
+	SquadFull       bool          `json:"squadFull,omitempty"`
+	BudgetExhausted bool          `json:"budgetExhausted,omitempty"`
 }
 
-func NewAuctionService(broadcast func(roomID string, eventType interface{}, data interface{}), redis *storage.RedisStore) *AuctionService {
+func NewAuctionService(broadcast func(roomID string, eventType interface{}, data interface{}), teams TeamStore) *AuctionService {
 	return &AuctionService{
 		State:     make(map[string]*AuctionState),
 		Broadcast: broadcast,
-		Redis:     redis,
+		Teams:     teams,
+		Lineups:   lineup.NewLineupService(),
 	}
 }
 
-func (a *AuctionService) StartAuctionByPositions(roomID string, posMap map[string]int) error {
-	var positions []PositionAuction
-	for pos, count := range posMap {
-		players, err := storage.FetchRandomPlayersByPosition(pos, count)
-		if err != nil {
-			return err
-		}
-		positions = append(positions, PositionAuction{
-			Position: pos,
-			Players:  players,
-			Index:    0,
-		})
+// Start runs a single auction over an already drawn list of players.
+func (a *AuctionService) Start(roomID string, players []domain.Player, quota SquadQuota, budget int) error {
+	a.start(roomID, []PositionAuction{{Position: "ANY", Players: players}}, quota, budget)
+	return nil
+}
+
+// StartAuctionByPositions draws posMap[pos] players for every position from
+// the pool generator and auctions them position by position.
+func (a *AuctionService) StartAuctionByPositions(roomID string, posMap map[string]int, pool *PoolGenerator, quota SquadQuota, budget int) error {
+	positions, err := pool.Generate(posMap)
+	if err != nil {
+		return err
 	}
+	a.start(roomID, positions, quota, budget)
+	return nil
+}
+
+func (a *AuctionService) start(roomID string, positions []PositionAuction, quota SquadQuota, budget int) {
 	a.StateMutex.Lock()
-	state := &AuctionState{Positions: positions, CurrentPos: 0}
+	state := &AuctionState{
+		Positions:  positions,
+		CurrentPos: 0,
+		Quota:      quota,
+		Squads:     make(map[string][]domain.Player),
+		Budget:     max(budget, 0),
+		Spent:      make(map[string]int),
+	}
 	a.State[roomID] = state
 	a.StateMutex.Unlock()
 	a.broadcastNextPlayer(roomID)
-	return nil
 }
 
 func (a *AuctionService) broadcastNextPlayer(roomID string) {
 	a.StateMutex.Lock()
-	state := a.State[roomID]
-	if state.CurrentPos >= len(state.Positions) {
+	state, ok := a.State[roomID]
+	if !ok {
 		a.StateMutex.Unlock()
-		return // Auction complete
+		return // Auction stopped
 	}
-	posAuction := &state.Positions[state.CurrentPos]
-	if posAuction.Index >= len(posAuction.Players) {
+	// Skip exhausted (or empty) positions
+	for state.CurrentPos < len(state.Positions) &&
+		state.Positions[state.CurrentPos].Index >= len(state.Positions[state.CurrentPos].Players) {
 		state.CurrentPos++
-		if state.CurrentPos < len(state.Positions) {
-			posAuction = &state.Positions[state.CurrentPos]
-			posAuction.Index = 0
-		} else {
-			a.StateMutex.Unlock()
-			return // Auction complete
-		}
 	}
+	if state.CurrentPos >= len(state.Positions) {
+		delete(a.State, roomID)
+		a.StateMutex.Unlock()
+		a.complete(roomID)
+		return
+	}
+	posAuction := &state.Positions[state.CurrentPos]
 	player := posAuction.Players[posAuction.Index]
 	state.CurrentBid = 0
 	state.CurrentBidder = ""
@@ -95,7 +137,7 @@ func (a *AuctionService) broadcastNextPlayer(roomID string) {
 	if state.Timer != nil {
 		state.Timer.Stop()
 	}
-	state.Timer = time.AfterFunc(10*time.Second, func() {
+	state.Timer = time.AfterFunc(BidWindow, func() {
 		a.finishAuction(roomID)
 	})
 	a.StateMutex.Unlock()
@@ -111,10 +153,70 @@ func (a *AuctionService) broadcastNextPlayer(roomID string) {
 	})
 }
 
+func (a *AuctionService) complete(roomID string) {
+	a.Broadcast(roomID, "auctionComplete", map[string]interface{}{"roomId": roomID})
+	if a.OnComplete != nil {
+		a.OnComplete(roomID)
+	}
+}
+
+// Pause stops the clock on the current player; bids are rejected until Resume.
+func (a *AuctionService) Pause(roomID string) {
+	a.StateMutex.Lock()
+	defer a.StateMutex.Unlock()
+	state, ok := a.State[roomID]
+	if !ok {
+		return
+	}
+	state.Paused = true
+	if state.Timer != nil {
+		state.Timer.Stop()
+	}
+}
+
+// Resume restarts a full bid window for the current player.
+func (a *AuctionService) Resume(roomID string) {
+	a.StateMutex.Lock()
+	defer a.StateMutex.Unlock()
+	state, ok := a.State[roomID]
+	if !ok || !state.Paused {
+		return
+	}
+	state.Paused = false
+	state.Timer = time.AfterFunc(BidWindow, func() {
+		a.finishAuction(roomID)
+	})
+}
+
+// Stop abandons the room's auction without completing it.
+func (a *AuctionService) Stop(roomID string) {
+	a.StateMutex.Lock()
+	defer a.StateMutex.Unlock()
+	state, ok := a.State[roomID]
+	if !ok {
+		return
+	}
+	if state.Timer != nil {
+		state.Timer.Stop()
+	}
+	delete(a.State, roomID)
+}
+
 func (a *AuctionService) PlaceBid(roomID, userID string, bid int) bool {
 	a.StateMutex.Lock()
 	defer a.StateMutex.Unlock()
-	state := a.State[roomID]
+	state, ok := a.State[roomID]
+	if !ok || state.Paused {
+		return false
+	}
+	posAuction := &state.Positions[state.CurrentPos]
+	player := posAuction.Players[posAuction.Index]
+	if !state.Quota.CanAdd(state.Squads[userID], player) {
+		return false // Squad has no room for this player's position
+	}
+	if state.Budget > 0 && bid > state.Budget-state.Spent[userID] {
+		return false // Over the bidder's remaining budget
+	}
 	if bid > state.CurrentBid {
 		state.CurrentBid = bid
 		state.CurrentBidder = userID
@@ -125,8 +227,6 @@ func (a *AuctionService) PlaceBid(roomID, userID string, bid int) bool {
 			Timestamp: time.Now(),
 		})
 		// Broadcast updated bid history
-		posAuction := &state.Positions[state.CurrentPos]
-		player := posAuction.Players[posAuction.Index]
 		a.Broadcast(roomID, "bidHistory", map[string]interface{}{
 			"position": posAuction.Position,
 			"player":   player,
@@ -139,17 +239,30 @@ func (a *AuctionService) PlaceBid(roomID, userID string, bid int) bool {
 
 func (a *AuctionService) finishAuction(roomID string) {
 	a.StateMutex.Lock()
-	state := a.State[roomID]
+	state, ok := a.State[roomID]
+	if !ok || state.Paused {
+		a.StateMutex.Unlock()
+		return
+	}
 	posAuction := &state.Positions[state.CurrentPos]
 	player := posAuction.Players[posAuction.Index]
 	winner := state.CurrentBidder
 	bid := state.CurrentBid
 	posAuction.Index++
-	complete := state.CurrentPos >= len(state.Positions) && posAuction.Index >= len(posAuction.Players)
+	sale := Sale{Player: player, Winner: winner, Bid: bid}
+	if winner != "" {
+		state.Squads[winner] = append(state.Squads[winner], player)
+		sale.SquadFull = state.Quota.Full(state.Squads[winner])
+		state.Spent[winner] += bid
+		sale.BudgetExhausted = state.Budget > 0 && state.Spent[winner] >= state.Budget
+	}
+	summary := a.summary(roomID, state)
 	a.StateMutex.Unlock()
 
-	if winner != "" && a.Redis != nil {
-		a.Redis.AddPlayerToTeam(roomID, winner, player)
+	if winner != "" && a.Teams != nil {
+		if err := a.Teams.AddPlayerToTeam(context.Background(), roomID, winner, player); err != nil {
+			logger.Error(fmt.Sprintf("saving %s to the squad of %s in room %s: %v", player.Name, winner, roomID, err))
+		}
 	}
 	a.Broadcast(roomID, "playerSold", map[string]interface{}{
 		"position": posAuction.Position,
@@ -158,7 +271,35 @@ func (a *AuctionService) finishAuction(roomID string) {
 		"bid":      bid,
 	})
 
-	if !complete {
-		a.broadcastNextPlayer(roomID)
+	a.Broadcast(roomID, "auctionSummary", summary)
+	if a.OnSold != nil {
+		a.OnSold(roomID, sale)
+	}
+	a.broadcastNextPlayer(roomID)
+}
+
+// ManagerSummary is one manager's standing in the auctionSummary event.
+type ManagerSummary struct {
+	UserID     string            `json:"userId"`
+	NumPlayers int               `json:"numPlayers"`
+	Rating     domain.TeamRating `json:"rating"`
+	Spent      int               `json:"spent"`
+}
+
+// summary rates every manager's squad using its best automatic lineup, so
+// managers see the impact of each purchase as it happens. Callers must hold
+// StateMutex.
+func (a *AuctionService) summary(roomID string, state *AuctionState) map[string]interface{} {
+	managers := make([]ManagerSummary, 0, len(state.Squads))
+	for userID, squad := range state.Squads {
+		auto := a.Lineups.Auto(roomID, userID, domain.DefaultFormation, squad)
+		managers = append(managers, ManagerSummary{
+			UserID:     userID,
+			NumPlayers: len(squad),
+			Rating:     a.Lineups.Rate(auto, squad),
+			Spent:      state.Spent[userID],
+		})
 	}
+	sort.Slice(managers, func(i, j int) bool { return managers[i].Rating.Rating > managers[j].Rating.Rating })
+	return map[string]interface{}{"managers": managers}
 }
@@ -0,0 +1,156 @@
+package auction
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+const MaxCustomPoolSize = 2000
+
+// ParsePlayerPool reads a host-uploaded player list. format is "csv" or
+// "json"; JSON is an array of players and CSV has a header row whose columns
+// are domain.Player field names or their bson names ("Preferred Foot").
+func ParsePlayerPool(format string, r io.Reader) ([]domain.Player, error) {
+	var players []domain.Player
+	switch format {
+	case "json":
+		if err := json.NewDecoder(r).Decode(&players); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	case "csv":
+		var err error
+		if players, err = parsePlayerCSV(r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err := ValidatePlayerPool(players); err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+// ValidatePlayerPool checks every player and assigns IDs to players without
+// one so the pool generator can de-duplicate them.
+func ValidatePlayerPool(players []domain.Player) error {
+	if len(players) == 0 {
+		return errors.New("player pool is empty")
+	}
+	if len(players) > MaxCustomPoolSize {
+		return fmt.Errorf("player pool has %d players, max is %d", len(players), MaxCustomPoolSize)
+	}
+	seen := make(map[string]bool)
+	for i := range players {
+		p := &players[i]
+		row := i + 1
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("player %d: name is required", row)
+		}
+		if len(p.Positions()) == 0 {
+			return fmt.Errorf("player %d (%s): unknown position %q", row, p.Name, p.Position)
+		}
+		if p.Overall < 1 || p.Overall > 99 {
+			return fmt.Errorf("player %d (%s): overall must be between 1 and 99", row, p.Name)
+		}
+		if p.Age < 0 || p.Value < 0 {
+			return fmt.Errorf("player %d (%s): age and value must not be negative", row, p.Name)
+		}
+		if p.ID == "" {
+			p.ID = "custom-" + strconv.Itoa(row)
+		}
+		if seen[p.ID] {
+			return fmt.Errorf("player %d (%s): duplicate id %q", row, p.Name, p.ID)
+		}
+		seen[p.ID] = true
+	}
+	return nil
+}
+
+func parsePlayerCSV(r io.Reader) ([]domain.Player, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	fields := playerFieldIndex()
+	columns := make([]int, len(header))
+	for i, name := range header {
+		idx, ok := fields[normalizeColumn(name)]
+		if !ok {
+			idx = -1 // ignore unknown columns
+		}
+		columns[i] = idx
+	}
+
+	var players []domain.Player
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		var p domain.Player
+		v := reflect.ValueOf(&p).Elem()
+		for i, cell := range record {
+			if i >= len(columns) || columns[i] < 0 || cell == "" {
+				continue
+			}
+			if err := setPlayerField(v.Field(columns[i]), cell); err != nil {
+				return nil, fmt.Errorf("line %d, column %q: %w", line, header[i], err)
+			}
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+// playerFieldIndex maps normalised Go and bson field names to field indexes.
+func playerFieldIndex() map[string]int {
+	t := reflect.TypeOf(domain.Player{})
+	index := make(map[string]int, t.NumField()*2)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index[normalizeColumn(f.Name)] = i
+		if tag := strings.Split(f.Tag.Get("bson"), ",")[0]; tag != "" {
+			index[normalizeColumn(tag)] = i
+		}
+	}
+	return index
+}
+
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.TrimSpace(name)))
+}
+
+func setPlayerField(f reflect.Value, cell string) error {
+	cell = strings.TrimSpace(cell)
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(cell)
+	case reflect.Int:
+		n, err := strconv.Atoi(cell)
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", cell)
+		}
+		f.SetInt(int64(n))
+	case reflect.Slice:
+		parts := strings.FieldsFunc(cell, func(r rune) bool { return r == ',' || r == ';' || r == '|' })
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		f.Set(reflect.ValueOf(parts))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package competition
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// LeagueFixtures builds a round-robin schedule using the circle method: every
+// manager meets every other once per leg, home and away swapping between
+// legs. With an odd number of managers one sits out each round.
+func LeagueFixtures(managers []string, legs int) []domain.Fixture {
+	if legs < 1 {
+		legs = 1
+	}
+	teams := append([]string{}, managers...)
+	if len(teams)%2 == 1 {
+		teams = append(teams, "") // rest slot
+	}
+	n := len(teams)
+	var fixtures []domain.Fixture
+	round := 0
+	for leg := 0; leg < legs; leg++ {
+		rotation := append([]string{}, teams...)
+		for r := 0; r < n-1; r++ {
+			round++
+			for i := 0; i < n/2; i++ {
+				home, away := rotation[i], rotation[n-1-i]
+				if home == "" || away == "" {
+					continue
+				}
+				if (r+leg)%2 == 1 {
+					home, away = away, home
+				}
+				fixtures = append(fixtures, domain.Fixture{
+					ID:         fmt.Sprintf("r%d-%d", round, i+1),
+					Round:      round,
+					HomeUserID: home,
+					AwayUserID: away,
+				})
+			}
+			// Keep the first team fixed and rotate the rest clockwise.
+			last := rotation[n-1]
+			copy(rotation[2:], rotation[1:n-1])
+			rotation[1] = last
+		}
+	}
+	return fixtures
+}
+
+// KnockoutRound pairs the entrants, given in seed order, for one round. When
+// the field is not a power of two the top seeds get byes so that the next
+// round is; otherwise the highest remaining seed meets the lowest.
+func KnockoutRound(round int, entrants []string) []domain.Fixture {
+	size := 1
+	for size < len(entrants) {
+		size *= 2
+	}
+	byes := size - len(entrants)
+	var fixtures []domain.Fixture
+	for i := 0; i < byes; i++ {
+		fixtures = append(fixtures, domain.Fixture{
+			ID:           fmt.Sprintf("r%d-bye%d", round, i+1),
+			Round:        round,
+			HomeUserID:   entrants[i],
+			Bye:          true,
+			Played:       true,
+			WinnerUserID: entrants[i],
+		})
+	}
+	rest := entrants[byes:]
+	for i := 0; i < len(rest)/2; i++ {
+		fixtures = append(fixtures, domain.Fixture{
+			ID:         fmt.Sprintf("r%d-%d", round, i+1),
+			Round:      round,
+			HomeUserID: rest[i],
+			AwayUserID: rest[len(rest)-1-i],
+		})
+	}
+	return fixtures
+}
+
+// KnockoutRounds is the number of rounds needed to find a winner.
+func KnockoutRounds(entrants int) int {
+	rounds := 0
+	for size := 1; size < entrants; size *= 2 {
+		rounds++
+	}
+	return rounds
+}
+
+// Standings tallies played league fixtures. Teams are ordered by points,
+// goal difference, goals scored, then user ID.
+func Standings(managers []string, fixtures []domain.Fixture) []domain.Standing {
+	table := make(map[string]*domain.Standing, len(managers))
+	for _, m := range managers {
+		table[m] = &domain.Standing{UserID: m}
+	}
+	for _, f := range fixtures {
+		if !f.Played || f.Bye {
+			continue
+		}
+		home, away := table[f.HomeUserID], table[f.AwayUserID]
+		if home == nil || away == nil {
+			continue
+		}
+		home.Played++
+		away.Played++
+		home.GoalsFor += f.HomeGoals
+		home.GoalsAgainst += f.AwayGoals
+		away.GoalsFor += f.AwayGoals
+		away.GoalsAgainst += f.HomeGoals
+		switch {
+		case f.HomeGoals > f.AwayGoals:
+			home.Won++
+			home.Points += 3
+			away.Lost++
+		case f.HomeGoals < f.AwayGoals:
+			away.Won++
+			away.Points += 3
+			home.Lost++
+		default:
+			home.Drawn++
+			away.Drawn++
+			home.Points++
+			away.Points++
+		}
+	}
+	standings := make([]domain.Standing, 0, len(table))
+	for _, s := range table {
+		standings = append(standings, *s)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.GoalDifference() != b.GoalDifference() {
+			return a.GoalDifference() > b.GoalDifference()
+		}
+		if a.GoalsFor != b.GoalsFor {
+			return a.GoalsFor > b.GoalsFor
+		}
+		return a.UserID < b.UserID
+	})
+	return standings
+}
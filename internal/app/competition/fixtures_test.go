@@ -0,0 +1,201 @@
+package competition
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+func managers(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("m%d", i+1)
+	}
+	return ids
+}
+
+func TestLeagueFixtures(t *testing.T) {
+	for _, n := range []int{2, 3, 4, 5, 6, 7} {
+		for _, legs := range []int{1, 2} {
+			t.Run(fmt.Sprintf("%d managers, %d legs", n, legs), func(t *testing.T) {
+				fixtures := LeagueFixtures(managers(n), legs)
+
+				if want := legs * n * (n - 1) / 2; len(fixtures) != want {
+					t.Errorf("%d fixtures, want %d", len(fixtures), want)
+				}
+				roundsPerLeg := n - 1
+				if n%2 == 1 {
+					roundsPerLeg = n
+				}
+				ids := map[string]bool{}
+				homes := map[[2]string]int{} // home, away -> meetings
+				playing := map[int]map[string]bool{}
+				for _, f := range fixtures {
+					if ids[f.ID] {
+						t.Errorf("fixture ID %s used twice", f.ID)
+					}
+					ids[f.ID] = true
+					if f.Round < 1 || f.Round > legs*roundsPerLeg {
+						t.Errorf("fixture %s in round %d of %d", f.ID, f.Round, legs*roundsPerLeg)
+					}
+					if f.HomeUserID == "" || f.AwayUserID == "" || f.HomeUserID == f.AwayUserID {
+						t.Errorf("fixture %s is %q v %q", f.ID, f.HomeUserID, f.AwayUserID)
+					}
+					if playing[f.Round] == nil {
+						playing[f.Round] = map[string]bool{}
+					}
+					for _, m := range []string{f.HomeUserID, f.AwayUserID} {
+						if playing[f.Round][m] {
+							t.Errorf("%s plays twice in round %d", m, f.Round)
+						}
+						playing[f.Round][m] = true
+					}
+					homes[[2]string{f.HomeUserID, f.AwayUserID}]++
+				}
+
+				ms := managers(n)
+				for i, a := range ms {
+					for _, b := range ms[i+1:] {
+						ab, ba := homes[[2]string{a, b}], homes[[2]string{b, a}]
+						if ab+ba != legs {
+							t.Errorf("%s and %s meet %d times, want %d", a, b, ab+ba, legs)
+						}
+						if legs == 2 && (ab != 1 || ba != 1) {
+							t.Errorf("%s hosts %s %d times and is hosted %d times, want once each", a, b, ab, ba)
+						}
+					}
+				}
+				for round, teams := range playing {
+					if want := n - n%2; len(teams) != want {
+						t.Errorf("%d managers play in round %d, want %d", len(teams), round, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestKnockoutRound(t *testing.T) {
+	tests := []struct {
+		entrants int
+		byes     []string
+		pairs    [][2]string
+	}{
+		{2, nil, [][2]string{{"m1", "m2"}}},
+		{3, []string{"m1"}, [][2]string{{"m2", "m3"}}},
+		{4, nil, [][2]string{{"m1", "m4"}, {"m2", "m3"}}},
+		{5, []string{"m1", "m2", "m3"}, [][2]string{{"m4", "m5"}}},
+		{6, []string{"m1", "m2"}, [][2]string{{"m3", "m6"}, {"m4", "m5"}}},
+		{8, nil, [][2]string{{"m1", "m8"}, {"m2", "m7"}, {"m3", "m6"}, {"m4", "m5"}}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d entrants", tt.entrants), func(t *testing.T) {
+			var byes []string
+			var pairs [][2]string
+			for _, f := range KnockoutRound(1, managers(tt.entrants)) {
+				if f.Round != 1 {
+					t.Errorf("fixture %s in round %d", f.ID, f.Round)
+				}
+				if f.Bye {
+					if !f.Played || f.WinnerUserID != f.HomeUserID || f.AwayUserID != "" {
+						t.Errorf("bye %+v does not advance its entrant", f)
+					}
+					byes = append(byes, f.HomeUserID)
+					continue
+				}
+				pairs = append(pairs, [2]string{f.HomeUserID, f.AwayUserID})
+			}
+			if !reflect.DeepEqual(byes, tt.byes) {
+				t.Errorf("byes = %v, want %v", byes, tt.byes)
+			}
+			if !reflect.DeepEqual(pairs, tt.pairs) {
+				t.Errorf("pairs = %v, want %v", pairs, tt.pairs)
+			}
+			// The next round is a power of two.
+			if next := len(byes) + len(pairs); next&(next-1) != 0 {
+				t.Errorf("%d go through, not a power of two", next)
+			}
+		})
+	}
+}
+
+func TestKnockoutRounds(t *testing.T) {
+	for entrants, want := range map[int]int{1: 0, 2: 1, 3: 2, 4: 2, 5: 3, 8: 3, 9: 4} {
+		if got := KnockoutRounds(entrants); got != want {
+			t.Errorf("KnockoutRounds(%d) = %d, want %d", entrants, got, want)
+		}
+	}
+}
+
+func played(home, away string, homeGoals, awayGoals int) domain.Fixture {
+	return domain.Fixture{HomeUserID: home, AwayUserID: away, HomeGoals: homeGoals, AwayGoals: awayGoals, Played: true}
+}
+
+func TestStandings(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures []domain.Fixture
+		want     []string
+	}{
+		{
+			"points",
+			[]domain.Fixture{played("b", "c", 1, 0), played("a", "c", 3, 3)},
+			[]string{"b", "a", "c"},
+		},
+		{
+			// a and b both win once; a by more.
+			"goal difference",
+			[]domain.Fixture{played("a", "c", 3, 0), played("b", "c", 1, 0)},
+			[]string{"a", "b", "c"},
+		},
+		{
+			// b and a both win by two; b scores more.
+			"goals scored",
+			[]domain.Fixture{played("a", "c", 2, 0), played("b", "c", 4, 2)},
+			[]string{"b", "a", "c"},
+		},
+		{
+			// a and c draw 1-1 with b and are level on everything else.
+			"user ID",
+			[]domain.Fixture{played("c", "b", 1, 1), played("a", "b", 1, 1)},
+			[]string{"b", "a", "c"},
+		},
+		{
+			// c would lead if the unplayed fixture or the bye counted.
+			"unplayed fixtures and byes",
+			[]domain.Fixture{
+				{HomeUserID: "c", AwayUserID: "b", HomeGoals: 5},
+				{HomeUserID: "c", Bye: true, Played: true, WinnerUserID: "c"},
+				played("b", "a", 1, 0),
+			},
+			[]string{"b", "c", "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order []string
+			for _, s := range Standings([]string{"a", "b", "c"}, tt.fixtures) {
+				order = append(order, s.UserID)
+			}
+			if !reflect.DeepEqual(order, tt.want) {
+				t.Errorf("order = %v, want %v", order, tt.want)
+			}
+		})
+	}
+}
+
+func TestStandingsTally(t *testing.T) {
+	standings := Standings([]string{"a", "b"}, []domain.Fixture{
+		played("a", "b", 2, 1),
+		played("b", "a", 1, 1),
+	})
+	want := []domain.Standing{
+		{UserID: "a", Played: 2, Won: 1, Drawn: 1, GoalsFor: 3, GoalsAgainst: 2, Points: 4},
+		{UserID: "b", Played: 2, Drawn: 1, Lost: 1, GoalsFor: 2, GoalsAgainst: 3, Points: 1},
+	}
+	if !reflect.DeepEqual(standings, want) {
+		t.Errorf("standings = %+v, want %+v", standings, want)
+	}
+}
@@ -0,0 +1,256 @@
+package competition
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourusername/TouchlineTactics/internal/app/lineup"
+	"github.com/yourusername/TouchlineTactics/internal/app/match"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+var (
+	ErrTooFewManagers = errors.New("a competition needs at least two managers")
+	ErrAlreadyRunning = errors.New("a competition is already running in this room")
+	ErrUnknownFormat  = errors.New("unknown competition format")
+)
+
+type CompetitionState struct {
+	Competition domain.Competition
+	Teams       func(userID string) match.Team
+	OnFinish    func(domain.Competition)
+	rng         *rand.Rand
+	pending     int // matches of the current round still streaming
+	cancelled   bool
+}
+
+type CompetitionService struct {
+	State      map[string]*CompetitionState // roomID -> state
+	StateMutex sync.Mutex
+	Matches    *match.MatchService
+	Lineups    *lineup.LineupService
+	Broadcast  func(roomID string, eventType interface{}, data interface{})
+}
+
+func NewCompetitionService(matches *match.MatchService, broadcast func(roomID string, eventType interface{}, data interface{})) *CompetitionService {
+	return &CompetitionService{
+		State:     make(map[string]*CompetitionState),
+		Matches:   matches,
+		Lineups:   lineup.NewLineupService(),
+		Broadcast: broadcast,
+	}
+}
+
+// Start creates the competition and kicks off round one. teams loads a
+// manager's current lineup and squad when each of their matches starts;
+// onFinish is called once a champion is known.
+func (s *CompetitionService) Start(roomID string, format domain.CompetitionFormat, managers []string, legs int, seed int64, teams func(userID string) match.Team, onFinish func(domain.Competition)) (domain.Competition, error) {
+	if len(managers) < 2 {
+		return domain.Competition{}, ErrTooFewManagers
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	comp := domain.Competition{
+		RoomID:   roomID,
+		Format:   format,
+		Managers: append([]string{}, managers...),
+		Round:    1,
+		Seed:     seed,
+	}
+	switch format {
+	case domain.CompetitionLeague:
+		comp.Fixtures = LeagueFixtures(comp.Managers, legs)
+		comp.Rounds = comp.Fixtures[len(comp.Fixtures)-1].Round
+		comp.Standings = Standings(comp.Managers, comp.Fixtures)
+	case domain.CompetitionKnockout:
+		seeded := s.seedByRating(comp.Managers, teams)
+		comp.Rounds = KnockoutRounds(len(seeded))
+		comp.Fixtures = KnockoutRound(1, seeded)
+	default:
+		return domain.Competition{}, ErrUnknownFormat
+	}
+
+	s.StateMutex.Lock()
+	if existing, ok := s.State[roomID]; ok && !existing.Competition.Finished {
+		s.StateMutex.Unlock()
+		return domain.Competition{}, ErrAlreadyRunning
+	}
+	state := &CompetitionState{
+		Competition: comp,
+		Teams:       teams,
+		OnFinish:    onFinish,
+		rng:         rand.New(rand.NewSource(seed)),
+	}
+	s.State[roomID] = state
+	s.StateMutex.Unlock()
+
+	s.Broadcast(roomID, "competitionUpdate", comp)
+	s.playRound(roomID)
+	return comp, nil
+}
+
+// Get returns a snapshot of the room's competition.
+func (s *CompetitionService) Get(roomID string) (domain.Competition, bool) {
+	s.StateMutex.Lock()
+	defer s.StateMutex.Unlock()
+	state, ok := s.State[roomID]
+	if !ok {
+		return domain.Competition{}, false
+	}
+	return snapshot(state.Competition), true
+}
+
+// Cancel stops the competition after the matches already streaming; no
+// further rounds are played and onFinish is not called.
+func (s *CompetitionService) Cancel(roomID string) {
+	s.StateMutex.Lock()
+	defer s.StateMutex.Unlock()
+	if state, ok := s.State[roomID]; ok {
+		state.cancelled = true
+		state.Competition.Finished = true
+	}
+}
+
+// seedByRating orders managers strongest first so top seeds get knockout byes.
+func (s *CompetitionService) seedByRating(managers []string, teams func(string) match.Team) []string {
+	ratings := make(map[string]float64, len(managers))
+	for _, m := range managers {
+		t := teams(m)
+		if t.Lineup != nil {
+			ratings[m] = s.Lineups.Rate(t.Lineup, t.Squad).Rating
+		}
+	}
+	seeded := append([]string{}, managers...)
+	sort.SliceStable(seeded, func(i, j int) bool { return ratings[seeded[i]] > ratings[seeded[j]] })
+	return seeded
+}
+
+// playRound starts every unplayed fixture of the current round. Matches are
+// simulated up front with seeds derived from the competition seed and
+// streamed to the room concurrently.
+func (s *CompetitionService) playRound(roomID string) {
+	s.StateMutex.Lock()
+	state := s.State[roomID]
+	comp := &state.Competition
+	var toPlay []int
+	var fixtures []domain.Fixture
+	var seeds []int64
+	for i, f := range comp.Fixtures {
+		if f.Round == comp.Round && !f.Played {
+			toPlay = append(toPlay, i)
+			fixtures = append(fixtures, f)
+			seeds = append(seeds, state.rng.Int63())
+		}
+	}
+	state.pending = len(toPlay)
+	s.StateMutex.Unlock()
+
+	if len(toPlay) == 0 {
+		s.finishRound(roomID)
+		return
+	}
+	for n, index := range toPlay {
+		index := index
+		home, away := state.Teams(fixtures[n].HomeUserID), state.Teams(fixtures[n].AwayUserID)
+		result := s.Matches.Play(roomID, home, away, seeds[n], func(result domain.MatchResult) {
+			s.recordResult(roomID, index, result)
+		})
+		s.StateMutex.Lock()
+		comp.Fixtures[index].MatchID = result.ID
+		s.StateMutex.Unlock()
+	}
+}
+
+func (s *CompetitionService) recordResult(roomID string, index int, result domain.MatchResult) {
+	s.StateMutex.Lock()
+	state := s.State[roomID]
+	comp := &state.Competition
+	f := &comp.Fixtures[index]
+	f.Played = true
+	f.HomeGoals = result.HomeGoals
+	f.AwayGoals = result.AwayGoals
+	switch {
+	case f.HomeGoals > f.AwayGoals:
+		f.WinnerUserID = f.HomeUserID
+	case f.AwayGoals > f.HomeGoals:
+		f.WinnerUserID = f.AwayUserID
+	case comp.Format == domain.CompetitionKnockout:
+		// Settle drawn ties with a shootout decided by the competition seed.
+		f.Penalties = true
+		f.WinnerUserID = f.HomeUserID
+		if state.rng.Intn(2) == 1 {
+			f.WinnerUserID = f.AwayUserID
+		}
+	}
+	if comp.Format == domain.CompetitionLeague {
+		comp.Standings = Standings(comp.Managers, comp.Fixtures)
+	}
+	state.pending--
+	roundDone := state.pending == 0
+	update := snapshot(*comp)
+	s.StateMutex.Unlock()
+
+	s.Broadcast(roomID, "competitionUpdate", update)
+	if roundDone {
+		s.finishRound(roomID)
+	}
+}
+
+// finishRound advances to the next round, drawing the next knockout round
+// from the winners, or crowns the champion after the last round.
+func (s *CompetitionService) finishRound(roomID string) {
+	s.StateMutex.Lock()
+	state := s.State[roomID]
+	comp := &state.Competition
+	if state.cancelled {
+		s.StateMutex.Unlock()
+		return
+	}
+	if comp.Round >= comp.Rounds {
+		comp.Finished = true
+		switch comp.Format {
+		case domain.CompetitionLeague:
+			comp.ChampionUserID = comp.Standings[0].UserID
+		case domain.CompetitionKnockout:
+			for _, f := range comp.Fixtures {
+				if f.Round == comp.Round {
+					comp.ChampionUserID = f.WinnerUserID
+				}
+			}
+		}
+		final := snapshot(*comp)
+		onFinish := state.OnFinish
+		s.StateMutex.Unlock()
+		s.Broadcast(roomID, "competitionUpdate", final)
+		if onFinish != nil {
+			onFinish(final)
+		}
+		return
+	}
+	if comp.Format == domain.CompetitionKnockout {
+		var winners []string
+		for _, f := range comp.Fixtures {
+			if f.Round == comp.Round {
+				winners = append(winners, f.WinnerUserID)
+			}
+		}
+		comp.Fixtures = append(comp.Fixtures, KnockoutRound(comp.Round+1, winners)...)
+	}
+	comp.Round++
+	update := snapshot(*comp)
+	s.StateMutex.Unlock()
+
+	s.Broadcast(roomID, "competitionUpdate", update)
+	s.playRound(roomID)
+}
+
+func snapshot(c domain.Competition) domain.Competition {
+	c.Managers = append([]string{}, c.Managers...)
+	c.Fixtures = append([]domain.Fixture{}, c.Fixtures...)
+	c.Standings = append([]domain.Standing(nil), c.Standings...)
+	return c
+}
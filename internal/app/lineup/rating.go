@@ -0,0 +1,186 @@
+package lineup
+
+import (
+	"math"
+	"strings"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// roleWeights scales each slot's contribution to the team rating.
+var roleWeights = map[string]float64{
+	"GK":  1.0,
+	"CB":  1.0,
+	"LB":  0.9,
+	"RB":  0.9,
+	"LWB": 0.9,
+	"RWB": 0.9,
+	"CDM": 1.0,
+	"CM":  1.0,
+	"CAM": 1.05,
+	"LM":  0.9,
+	"RM":  0.9,
+	"LW":  0.95,
+	"RW":  0.95,
+	"CF":  1.05,
+	"ST":  1.1,
+}
+
+const (
+	outOfPositionFactor = 0.9
+	linkRange           = 0.42 // max pitch distance between linked slots
+	maxPlayerChemistry  = 10
+	maxChemistryBonus   = 5.0 // rating points added at 100 chemistry
+)
+
+// Rate computes a lineup's strength. Each starter's Overall is adjusted for
+// playing out of position and for work rate and skill moves relative to the
+// slot, then averaged with role weights (empty slots count as zero). Players
+// earn chemistry from links to adjacent slots sharing a club or nationality,
+// and team chemistry adds a bonus of up to maxChemistryBonus points.
+func (s *LineupService) Rate(lineup *domain.Lineup, squad []domain.Player) domain.TeamRating {
+	formation, ok := domain.Formations[lineup.Formation]
+	if !ok {
+		formation = domain.Formations[domain.DefaultFormation]
+	}
+	players := squadIndex(squad)
+	rating := domain.TeamRating{
+		Lines:         make(map[domain.PositionLine]float64),
+		OutOfPosition: []string{},
+	}
+
+	var total, weights float64
+	lineTotals := make(map[domain.PositionLine]float64)
+	lineCounts := make(map[domain.PositionLine]int)
+	effective := make(map[string]float64)
+	for _, slot := range formation.Slots {
+		line, _ := domain.LineOf(slot.Role)
+		weight := roleWeights[slot.Role]
+		weights += weight
+		lineCounts[line]++
+		player, ok := players[lineup.Slots[slot.ID]]
+		if !ok {
+			continue
+		}
+		value := float64(player.Overall)
+		if !player.CanPlay(slot.Role) {
+			value *= outOfPositionFactor
+			rating.OutOfPosition = append(rating.OutOfPosition, slot.ID)
+		}
+		value += styleModifier(player, line)
+		effective[slot.ID] = value
+		total += weight * value
+		lineTotals[line] += value
+	}
+	if weights > 0 {
+		rating.Base = round1(total / weights)
+	}
+	for line, count := range lineCounts {
+		rating.Lines[line] = round1(lineTotals[line] / float64(count))
+	}
+
+	chemistry := make(map[string]int)
+	for _, slot := range formation.Slots {
+		if _, ok := effective[slot.ID]; ok {
+			chemistry[slot.ID] = maxPlayerChemistry / 2
+		}
+	}
+	for _, link := range formationLinks(formation) {
+		a, okA := players[lineup.Slots[link[0]]]
+		b, okB := players[lineup.Slots[link[1]]]
+		if !okA || !okB {
+			continue
+		}
+		score := 0
+		if a.Club != "" && a.Club == b.Club {
+			score += 2
+		}
+		if a.Nationality != "" && a.Nationality == b.Nationality {
+			score++
+		}
+		if score > 0 {
+			rating.Links++
+		}
+		chemistry[link[0]] += score
+		chemistry[link[1]] += score
+	}
+	for _, slotID := range rating.OutOfPosition {
+		chemistry[slotID] -= 3
+	}
+	sum := 0
+	for _, c := range chemistry {
+		sum += clamp(c, 0, maxPlayerChemistry)
+	}
+	rating.Chemistry = sum * 100 / (maxPlayerChemistry * len(formation.Slots))
+	rating.Rating = round1(rating.Base + maxChemistryBonus*float64(rating.Chemistry)/100)
+	return rating
+}
+
+// styleModifier rewards work rate in the player's half of the job (attack for
+// forwards, defence for defenders, both for midfielders) and skill moves for
+// attacking players.
+func styleModifier(p domain.Player, line domain.PositionLine) float64 {
+	attack, defence := parseWorkRate(p.WorkRate)
+	skill := 0.0
+	if p.SkillMoves > 0 {
+		skill = float64(p.SkillMoves - 3)
+	}
+	switch line {
+	case domain.LineForward:
+		return attack + 0.5*skill
+	case domain.LineMidfield:
+		return (attack+defence)/2 + 0.25*skill
+	case domain.LineDefence:
+		return defence
+	}
+	return 0
+}
+
+// parseWorkRate turns "High/ Medium" into attack and defence modifiers.
+func parseWorkRate(workRate string) (attack, defence float64) {
+	parts := strings.Split(workRate, "/")
+	value := func(s string) float64 {
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "high":
+			return 1
+		case "low":
+			return -1
+		}
+		return 0
+	}
+	if len(parts) > 0 {
+		attack = value(parts[0])
+	}
+	if len(parts) > 1 {
+		defence = value(parts[1])
+	}
+	return attack, defence
+}
+
+// formationLinks returns the pairs of slots close enough on the pitch to
+// link for chemistry.
+func formationLinks(f domain.Formation) [][2]string {
+	var links [][2]string
+	for i, a := range f.Slots {
+		for _, b := range f.Slots[i+1:] {
+			if math.Hypot(a.X-b.X, a.Y-b.Y) <= linkRange {
+				links = append(links, [2]string{a.ID, b.ID})
+			}
+		}
+	}
+	return links
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,42 @@
+package lineup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+func TestRatePenalisesOutOfPositionPlayers(t *testing.T) {
+	s := NewLineupService()
+	natural := []domain.Player{player("gk", "GK", 80), player("lb", "LB", 80)}
+	moved := []domain.Player{player("gk", "GK", 80), player("lb", "CB", 80)}
+	lineup := &domain.Lineup{Formation: "4-4-2", Slots: map[string]string{"GK": "gk", "LB": "lb"}}
+
+	want := s.Rate(lineup, natural)
+	got := s.Rate(lineup, moved)
+
+	if len(want.OutOfPosition) != 0 {
+		t.Errorf("natural fit reported out of position: %v", want.OutOfPosition)
+	}
+	if !reflect.DeepEqual(got.OutOfPosition, []string{"LB"}) {
+		t.Errorf("out of position = %v, want [LB]", got.OutOfPosition)
+	}
+	if got.Lines[domain.LineDefence] >= want.Lines[domain.LineDefence] {
+		t.Errorf("defence = %v playing out of position, want below %v", got.Lines[domain.LineDefence], want.Lines[domain.LineDefence])
+	}
+	if got.Base >= want.Base || got.Rating >= want.Rating {
+		t.Errorf("rating = %v (base %v) out of position, want below %v (base %v)", got.Rating, got.Base, want.Rating, want.Base)
+	}
+}
+
+func TestRateAutoLineupOfALineOnlySquad(t *testing.T) {
+	s := NewLineupService()
+	squad := lineOnlySquad()
+	rating := s.Rate(s.Auto("room", "user", "4-4-2", squad), squad)
+	// Centre backs cover the full backs and centre midfielders the wings.
+	want := []string{"LB", "RB", "LM", "RM"}
+	if !reflect.DeepEqual(rating.OutOfPosition, want) {
+		t.Errorf("out of position = %v, want %v", rating.OutOfPosition, want)
+	}
+}
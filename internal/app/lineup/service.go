@@ -0,0 +1,150 @@
+package lineup
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+const MaxBench = 7
+
+type LineupService struct{}
+
+func NewLineupService() *LineupService {
+	return &LineupService{}
+}
+
+// Validate checks a lineup against the manager's squad: the formation must
+// exist, every slot and bench entry must be a distinct squad player whose
+// lines include the slot's line, and the captain must be a starter. A player
+// from the right line who is not a natural fit for the role is allowed, as
+// squads are bought by line, and is penalised by Rate. Empty slots are
+// allowed so lineups can be saved while still being picked.
+func (s *LineupService) Validate(lineup *domain.Lineup, squad []domain.Player) error {
+	formation, ok := domain.Formations[lineup.Formation]
+	if !ok {
+		return fmt.Errorf("unknown formation %q", lineup.Formation)
+	}
+	players := squadIndex(squad)
+	slots := make(map[string]domain.FormationSlot, len(formation.Slots))
+	for _, slot := range formation.Slots {
+		slots[slot.ID] = slot
+	}
+
+	used := make(map[string]bool)
+	for slotID, playerID := range lineup.Slots {
+		slot, ok := slots[slotID]
+		if !ok {
+			return fmt.Errorf("formation %s has no slot %q", formation.Name, slotID)
+		}
+		if playerID == "" {
+			continue
+		}
+		player, ok := players[playerID]
+		if !ok {
+			return fmt.Errorf("player %q is not in your squad", playerID)
+		}
+		if used[playerID] {
+			return fmt.Errorf("%s is picked more than once", player.Name)
+		}
+		used[playerID] = true
+		line, _ := domain.LineOf(slot.Role)
+		if !player.CanPlay(string(line)) {
+			return fmt.Errorf("%s cannot play %s", player.Name, slot.ID)
+		}
+	}
+
+	if len(lineup.Bench) > MaxBench {
+		return fmt.Errorf("bench can hold at most %d players", MaxBench)
+	}
+	for _, playerID := range lineup.Bench {
+		player, ok := players[playerID]
+		if !ok {
+			return fmt.Errorf("player %q is not in your squad", playerID)
+		}
+		if used[playerID] {
+			return fmt.Errorf("%s is picked more than once", player.Name)
+		}
+		used[playerID] = true
+	}
+
+	if lineup.Captain != "" && !isStarter(lineup, lineup.Captain) {
+		return fmt.Errorf("captain must be in the starting lineup")
+	}
+	return nil
+}
+
+// Auto picks a lineup for the formation: each slot gets the highest rated
+// unused natural fit, falling back to the best player from the same line.
+// Up to MaxBench remaining players fill the bench, best first, and the
+// highest rated starter is captain.
+func (s *LineupService) Auto(roomID, userID, formationName string, squad []domain.Player) *domain.Lineup {
+	formation, ok := domain.Formations[formationName]
+	if !ok {
+		formation = domain.Formations[domain.DefaultFormation]
+	}
+	sorted := append([]domain.Player{}, squad...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Overall > sorted[j].Overall })
+
+	lineup := &domain.Lineup{
+		RoomID:    roomID,
+		UserID:    userID,
+		Formation: formation.Name,
+		Slots:     make(map[string]string),
+		Bench:     []string{},
+	}
+	used := make(map[string]bool)
+	pick := func(slot domain.FormationSlot, fits func(domain.Player) bool) {
+		if lineup.Slots[slot.ID] != "" {
+			return
+		}
+		for _, p := range sorted {
+			if !used[p.ID] && fits(p) {
+				used[p.ID] = true
+				lineup.Slots[slot.ID] = p.ID
+				return
+			}
+		}
+	}
+	for _, slot := range formation.Slots {
+		role := slot.Role
+		pick(slot, func(p domain.Player) bool { return p.CanPlay(role) })
+	}
+	for _, slot := range formation.Slots {
+		line, _ := domain.LineOf(slot.Role)
+		pick(slot, func(p domain.Player) bool { return p.CanPlay(string(line)) })
+	}
+	for _, p := range sorted {
+		if len(lineup.Bench) >= MaxBench {
+			break
+		}
+		if !used[p.ID] {
+			used[p.ID] = true
+			lineup.Bench = append(lineup.Bench, p.ID)
+		}
+	}
+	for _, p := range sorted {
+		if lineup.Captain == "" && isStarter(lineup, p.ID) {
+			lineup.Captain = p.ID
+		}
+	}
+	return lineup
+}
+
+func isStarter(lineup *domain.Lineup, playerID string) bool {
+	for _, id := range lineup.Slots {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+func squadIndex(squad []domain.Player) map[string]domain.Player {
+	players := make(map[string]domain.Player, len(squad))
+	for _, p := range squad {
+		players[p.ID] = p
+	}
+	return players
+}
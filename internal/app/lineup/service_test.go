@@ -0,0 +1,83 @@
+package lineup
+
+import (
+	"testing"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+func player(id, position string, overall int) domain.Player {
+	return domain.Player{ID: id, Name: id, Position: position, Overall: overall}
+}
+
+// lineOnlySquad is what an auction buying a 4-4-2 by line can leave a
+// manager with: centre backs and centre midfielders but no full backs or
+// wide midfielders.
+func lineOnlySquad() []domain.Player {
+	return []domain.Player{
+		player("gk", "GK", 80),
+		player("cb1", "CB", 84), player("cb2", "CB", 82), player("cb3", "CB", 80), player("cb4", "CB", 78),
+		player("cm1", "CM", 85), player("cm2", "CM", 83), player("cm3", "CM", 81), player("cm4", "CM", 79),
+		player("st1", "ST", 88), player("st2", "ST", 86),
+		player("cm5", "CM", 70),
+	}
+}
+
+func TestAutoFillsEverySlotFromALineOnlySquad(t *testing.T) {
+	s := NewLineupService()
+	squad := lineOnlySquad()
+	lineup := s.Auto("room", "user", "4-4-2", squad)
+
+	for _, slot := range domain.Formations["4-4-2"].Slots {
+		if lineup.Slots[slot.ID] == "" {
+			t.Errorf("slot %s left empty", slot.ID)
+		}
+	}
+	// Natural fits go first, so the best centre backs take the CB slots
+	// and the weakest is moved out wide.
+	if lineup.Slots["LCB"] != "cb1" || lineup.Slots["RCB"] != "cb2" {
+		t.Errorf("centre backs = %s, %s, want cb1, cb2", lineup.Slots["LCB"], lineup.Slots["RCB"])
+	}
+	if lineup.Slots["LB"] != "cb3" || lineup.Slots["RB"] != "cb4" {
+		t.Errorf("full backs = %s, %s, want cb3, cb4", lineup.Slots["LB"], lineup.Slots["RB"])
+	}
+	if len(lineup.Bench) != 1 || lineup.Bench[0] != "cm5" {
+		t.Errorf("bench = %v, want [cm5]", lineup.Bench)
+	}
+	if lineup.Captain != "st1" {
+		t.Errorf("captain = %s, want st1", lineup.Captain)
+	}
+	if err := s.Validate(lineup, squad); err != nil {
+		t.Errorf("auto lineup rejected: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := NewLineupService()
+	squad := lineOnlySquad()
+	tests := []struct {
+		name    string
+		lineup  domain.Lineup
+		wantErr bool
+	}{
+		{"empty", domain.Lineup{Formation: "4-4-2"}, false},
+		{"natural fit", domain.Lineup{Formation: "4-4-2", Slots: map[string]string{"LCB": "cb1"}}, false},
+		{"same line, other role", domain.Lineup{Formation: "4-4-2", Slots: map[string]string{"LB": "cb1", "LM": "cm1"}}, false},
+		{"other line", domain.Lineup{Formation: "4-4-2", Slots: map[string]string{"LM": "cb1"}}, true},
+		{"unknown formation", domain.Lineup{Formation: "1-1-8"}, true},
+		{"unknown slot", domain.Lineup{Formation: "4-4-2", Slots: map[string]string{"CDM": "cm1"}}, true},
+		{"not in squad", domain.Lineup{Formation: "4-4-2", Slots: map[string]string{"GK": "someone"}}, true},
+		{"picked twice", domain.Lineup{Formation: "4-4-2", Slots: map[string]string{"LCB": "cb1", "RCB": "cb1"}}, true},
+		{"starter on the bench", domain.Lineup{Formation: "4-4-2", Slots: map[string]string{"GK": "gk"}, Bench: []string{"gk"}}, true},
+		{"captain on the bench", domain.Lineup{Formation: "4-4-2", Bench: []string{"gk"}, Captain: "gk"}, true},
+		{"bench too long", domain.Lineup{Formation: "4-4-2", Bench: []string{"gk", "cb1", "cb2", "cb3", "cb4", "cm1", "cm2", "cm3"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate(&tt.lineup, squad)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package match
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+const DefaultMinuteDuration = time.Second
+
+type MatchService struct {
+	Broadcast func(roomID string, eventType interface{}, data interface{})
+	// MinuteDuration is the real time between streamed match minutes.
+	MinuteDuration time.Duration
+}
+
+func NewMatchService(broadcast func(roomID string, eventType interface{}, data interface{})) *MatchService {
+	return &MatchService{
+		Broadcast:      broadcast,
+		MinuteDuration: DefaultMinuteDuration,
+	}
+}
+
+// Play simulates a match and streams it to the room as "matchEvent"
+// messages, one match minute per MinuteDuration, followed by "matchResult".
+// done, if set, is called with the result once streaming finishes. A zero
+// seed picks one from the clock; the seed is part of the result.
+func (s *MatchService) Play(roomID string, home, away Team, seed int64, done func(domain.MatchResult)) domain.MatchResult {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	result := Simulate(home, away, seed)
+	result.ID = uuid.NewString()
+	result.RoomID = roomID
+	go s.stream(result, done)
+	return result
+}
+
+func (s *MatchService) stream(result domain.MatchResult, done func(domain.MatchResult)) {
+	s.Broadcast(result.RoomID, "matchStart", map[string]interface{}{
+		"matchId":    result.ID,
+		"homeUserId": result.HomeUserID,
+		"awayUserId": result.AwayUserID,
+		"seed":       result.Seed,
+	})
+	minute := 0
+	for _, e := range result.Events {
+		if e.Minute > minute {
+			time.Sleep(time.Duration(e.Minute-minute) * s.MinuteDuration)
+			minute = e.Minute
+		}
+		s.Broadcast(result.RoomID, "matchEvent", map[string]interface{}{
+			"matchId": result.ID,
+			"event":   e,
+		})
+	}
+	s.Broadcast(result.RoomID, "matchResult", result)
+	if done != nil {
+		done(result)
+	}
+}
@@ -0,0 +1,281 @@
+package match
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/yourusername/TouchlineTactics/internal/app/lineup"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+const (
+	MatchMinutes   = 90
+	MaxSubstitutes = 3
+
+	chanceBase     = 0.22 // chance probability per minute between equal sides
+	maxChance      = 0.6
+	goalBase       = 0.12 // conversion rate of an equal shooter vs keeper
+	maxGoal        = 0.5
+	yellowRate     = 0.012 // per team per minute
+	straightRed    = 0.0008
+	homeAdvantage  = 1.05
+	missingPlayer  = 40 // Overall used for an empty line
+	startingEleven = 11
+)
+
+// substitutionMinutes are when each side considers making a change.
+var substitutionMinutes = []int{60, 70, 80}
+
+// Team is one side of a match: a manager's lineup and the squad it refers to.
+type Team struct {
+	UserID string
+	Lineup *domain.Lineup
+	Squad  []domain.Player
+}
+
+type onPitch struct {
+	slot   domain.FormationSlot
+	line   domain.PositionLine
+	player domain.Player
+}
+
+type side struct {
+	name      domain.MatchSide
+	players   []onPitch
+	bench     []domain.Player
+	booked    map[string]bool
+	subs      int
+	chemistry float64
+}
+
+// Simulate plays a match minute by minute. The same teams and seed always
+// produce the same result, so it can run headless in tests or ahead of time
+// and be streamed to clients afterwards.
+func Simulate(home, away Team, seed int64) domain.MatchResult {
+	rng := rand.New(rand.NewSource(seed))
+	sides := [2]*side{newSide(domain.HomeSide, home), newSide(domain.AwaySide, away)}
+	result := domain.MatchResult{
+		HomeUserID: home.UserID,
+		AwayUserID: away.UserID,
+		Seed:       seed,
+		Events:     []domain.MatchEvent{},
+	}
+	emit := func(e domain.MatchEvent) {
+		e.HomeScore = result.HomeGoals
+		e.AwayScore = result.AwayGoals
+		result.Events = append(result.Events, e)
+	}
+
+	emit(domain.MatchEvent{Minute: 0, Type: domain.MatchKickOff})
+	for minute := 1; minute <= MatchMinutes; minute++ {
+		for _, s := range sides {
+			for _, m := range substitutionMinutes {
+				if m == minute {
+					if e, ok := s.substitute(); ok {
+						e.Minute = minute
+						emit(e)
+					}
+				}
+			}
+			if e, ok := s.discipline(rng); ok {
+				e.Minute = minute
+				emit(e)
+			}
+		}
+
+		homeControl := sides[0].control() * homeAdvantage
+		attacker, defender := sides[0], sides[1]
+		if rng.Float64() >= homeControl/(homeControl+sides[1].control()) {
+			attacker, defender = sides[1], sides[0]
+		}
+		ratio := attacker.attack() / defender.defence()
+		if rng.Float64() < math.Min(maxChance, chanceBase*ratio*ratio*ratio) {
+			shooter := attacker.pickShooter(rng)
+			keeper := defender.keeperOverall()
+			conversion := math.Min(maxGoal, goalBase*math.Pow(float64(shooter.Overall)/keeper, 2))
+			event := domain.MatchEvent{
+				Minute:     minute,
+				Type:       domain.MatchChance,
+				Side:       attacker.name,
+				PlayerID:   shooter.ID,
+				PlayerName: shooter.Name,
+			}
+			if rng.Float64() < conversion {
+				event.Type = domain.MatchGoal
+				if attacker.name == domain.HomeSide {
+					result.HomeGoals++
+				} else {
+					result.AwayGoals++
+				}
+			}
+			emit(event)
+		}
+
+		if minute == 45 {
+			emit(domain.MatchEvent{Minute: 45, Type: domain.MatchHalfTime})
+		}
+	}
+	emit(domain.MatchEvent{Minute: MatchMinutes, Type: domain.MatchFullTime})
+	return result
+}
+
+func newSide(name domain.MatchSide, team Team) *side {
+	s := &side{name: name, booked: make(map[string]bool), chemistry: 1}
+	squad := make(map[string]domain.Player, len(team.Squad))
+	for _, p := range team.Squad {
+		squad[p.ID] = p
+	}
+	if team.Lineup == nil {
+		return s
+	}
+	formation := domain.Formations[team.Lineup.Formation]
+	for _, slot := range formation.Slots {
+		p, ok := squad[team.Lineup.Slots[slot.ID]]
+		if !ok {
+			continue
+		}
+		line, _ := domain.LineOf(slot.Role)
+		s.players = append(s.players, onPitch{slot: slot, line: line, player: p})
+	}
+	for _, id := range team.Lineup.Bench {
+		if p, ok := squad[id]; ok {
+			s.bench = append(s.bench, p)
+		}
+	}
+	rating := lineup.NewLineupService().Rate(team.Lineup, team.Squad)
+	s.chemistry = 1 + float64(rating.Chemistry)/1000
+	return s
+}
+
+// lineAverage is the mean Overall of a line currently on the pitch.
+func (s *side) lineAverage(line domain.PositionLine) float64 {
+	total, n := 0, 0
+	for _, p := range s.players {
+		if p.line == line {
+			total += p.player.Overall
+			n++
+		}
+	}
+	if n == 0 {
+		return missingPlayer
+	}
+	return float64(total) / float64(n)
+}
+
+// strength scales a weighted line average by chemistry and by how many
+// players are left on the pitch.
+func (s *side) strength(gk, def, mid, fwd float64) float64 {
+	v := gk*s.lineAverage(domain.LineGoalkeeper) +
+		def*s.lineAverage(domain.LineDefence) +
+		mid*s.lineAverage(domain.LineMidfield) +
+		fwd*s.lineAverage(domain.LineForward)
+	return v * s.chemistry * float64(len(s.players)+1) / (startingEleven + 1)
+}
+
+func (s *side) attack() float64  { return s.strength(0, 0, 0.3, 0.7) }
+func (s *side) control() float64 { return s.strength(0, 0.2, 0.6, 0.2) }
+func (s *side) defence() float64 { return s.strength(0.2, 0.6, 0.2, 0) }
+
+func (s *side) keeperOverall() float64 {
+	return s.lineAverage(domain.LineGoalkeeper)
+}
+
+var shooterWeights = map[domain.PositionLine]float64{
+	domain.LineForward:  6,
+	domain.LineMidfield: 3,
+	domain.LineDefence:  1,
+}
+
+// pickShooter chooses who takes a chance, favouring forwards and better players.
+func (s *side) pickShooter(rng *rand.Rand) domain.Player {
+	total := 0.0
+	for _, p := range s.players {
+		total += shooterWeights[p.line] * float64(p.player.Overall)
+	}
+	if total == 0 {
+		return domain.Player{Overall: missingPlayer}
+	}
+	r := rng.Float64() * total
+	for _, p := range s.players {
+		r -= shooterWeights[p.line] * float64(p.player.Overall)
+		if r < 0 {
+			return p.player
+		}
+	}
+	return s.players[len(s.players)-1].player
+}
+
+// discipline books a random outfield player; a second booking or a rare
+// straight red sends them off.
+func (s *side) discipline(rng *rand.Rand) (domain.MatchEvent, bool) {
+	roll := rng.Float64()
+	if roll >= yellowRate+straightRed {
+		return domain.MatchEvent{}, false
+	}
+	var outfield []int
+	for i, p := range s.players {
+		if p.line != domain.LineGoalkeeper {
+			outfield = append(outfield, i)
+		}
+	}
+	if len(outfield) == 0 {
+		return domain.MatchEvent{}, false
+	}
+	i := outfield[rng.Intn(len(outfield))]
+	p := s.players[i].player
+	event := domain.MatchEvent{Type: domain.MatchYellowCard, Side: s.name, PlayerID: p.ID, PlayerName: p.Name}
+	if roll < straightRed || s.booked[p.ID] {
+		event.Type = domain.MatchRedCard
+		s.players = append(s.players[:i], s.players[i+1:]...)
+		return event, true
+	}
+	s.booked[p.ID] = true
+	return event, true
+}
+
+// substitute replaces the weakest outfield player, preferring booked players,
+// with the best bench player from the same line.
+func (s *side) substitute() (domain.MatchEvent, bool) {
+	if s.subs >= MaxSubstitutes || len(s.bench) == 0 {
+		return domain.MatchEvent{}, false
+	}
+	order := make([]int, 0, len(s.players))
+	for i, p := range s.players {
+		if p.line != domain.LineGoalkeeper {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		pa, pb := s.players[order[a]].player, s.players[order[b]].player
+		if s.booked[pa.ID] != s.booked[pb.ID] {
+			return s.booked[pa.ID]
+		}
+		return pa.Overall < pb.Overall
+	})
+	for _, i := range order {
+		off := s.players[i]
+		best := -1
+		for j, b := range s.bench {
+			if b.CanPlay(string(off.line)) && (best < 0 || b.Overall > s.bench[best].Overall) {
+				best = j
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		on := s.bench[best]
+		s.bench = append(s.bench[:best], s.bench[best+1:]...)
+		s.players[i].player = on
+		s.subs++
+		return domain.MatchEvent{
+			Type:          domain.MatchSubstitution,
+			Side:          s.name,
+			PlayerID:      off.player.ID,
+			PlayerName:    off.player.Name,
+			SubPlayerID:   on.ID,
+			SubPlayerName: on.Name,
+		}, true
+	}
+	return domain.MatchEvent{}, false
+}
@@ -0,0 +1,129 @@
+package match
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/TouchlineTactics/internal/app/lineup"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// team builds a 4-4-2 side with a three-player bench, every player rated
+// overall.
+func team(userID string, overall int) Team {
+	positions := []string{"GK", "LB", "CB", "CB", "RB", "LM", "CM", "CM", "RM", "ST", "ST", "CB", "CM", "ST"}
+	var squad []domain.Player
+	for i, pos := range positions {
+		id := fmt.Sprintf("%s-%d", userID, i)
+		squad = append(squad, domain.Player{ID: id, Name: id, Position: pos, Overall: overall})
+	}
+	return Team{
+		UserID: userID,
+		Lineup: lineup.NewLineupService().Auto("room", userID, "4-4-2", squad),
+		Squad:  squad,
+	}
+}
+
+func TestSimulateIsReproducible(t *testing.T) {
+	home, away := team("home", 80), team("away", 78)
+	first := Simulate(home, away, 42)
+	second := Simulate(home, away, 42)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different matches:\n%+v\n%+v", first, second)
+	}
+}
+
+func TestSimulateSeedsDiverge(t *testing.T) {
+	home, away := team("home", 80), team("away", 78)
+	base := Simulate(home, away, 1)
+	for seed := int64(2); seed <= 20; seed++ {
+		if !reflect.DeepEqual(Simulate(home, away, seed).Events, base.Events) {
+			return
+		}
+	}
+	t.Error("20 seeds all gave the same events")
+}
+
+func TestSimulateEventStream(t *testing.T) {
+	result := Simulate(team("home", 80), team("away", 78), 7)
+	events := result.Events
+	if len(events) < 3 {
+		t.Fatalf("events = %v", events)
+	}
+	if events[0].Type != domain.MatchKickOff {
+		t.Errorf("first event = %s, want kick-off", events[0].Type)
+	}
+	last := events[len(events)-1]
+	if last.Type != domain.MatchFullTime || last.Minute != MatchMinutes {
+		t.Errorf("last event = %s at %d, want full time at %d", last.Type, last.Minute, MatchMinutes)
+	}
+	if last.HomeScore != result.HomeGoals || last.AwayScore != result.AwayGoals {
+		t.Errorf("final score %d-%d, result %d-%d", last.HomeScore, last.AwayScore, result.HomeGoals, result.AwayGoals)
+	}
+	subs := map[domain.MatchSide]int{}
+	for i, e := range events[1:] {
+		if e.Minute < events[i].Minute {
+			t.Errorf("event %d at minute %d follows minute %d", i+1, e.Minute, events[i].Minute)
+		}
+		if e.Type == domain.MatchSubstitution {
+			subs[e.Side]++
+		}
+	}
+	for side, n := range subs {
+		if n > MaxSubstitutes {
+			t.Errorf("%s made %d substitutions", side, n)
+		}
+	}
+}
+
+func TestSimulateShortLineups(t *testing.T) {
+	full := team("full", 80)
+	short := team("short", 80)
+	short.Lineup = &domain.Lineup{
+		Formation: "4-4-2",
+		Slots:     map[string]string{"GK": "short-0", "LCB": "short-2", "RCB": "short-3"},
+	}
+	empty := Team{UserID: "empty", Lineup: &domain.Lineup{Formation: "4-4-2"}}
+	none := Team{UserID: "none"}
+
+	tests := []struct {
+		name       string
+		home, away Team
+		stronger   domain.MatchSide // "" when neither side has players
+	}{
+		{"short", full, short, domain.HomeSide},
+		{"empty", full, empty, domain.HomeSide},
+		{"no lineup", none, full, domain.AwaySide},
+		{"neither side", none, empty, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sides := map[domain.MatchSide]Team{domain.HomeSide: tt.home, domain.AwaySide: tt.away}
+			goals := map[domain.MatchSide]int{}
+			for seed := int64(0); seed < 20; seed++ {
+				result := Simulate(tt.home, tt.away, seed)
+				goals[domain.HomeSide] += result.HomeGoals
+				goals[domain.AwaySide] += result.AwayGoals
+				for _, e := range result.Events {
+					if e.Type != domain.MatchSubstitution && e.Type != domain.MatchYellowCard && e.Type != domain.MatchRedCard {
+						continue
+					}
+					if side := sides[e.Side]; side.Lineup == nil || len(side.Lineup.Slots) == 0 {
+						t.Errorf("%s for %s, who has no players", e.Type, side.UserID)
+					}
+				}
+			}
+			if tt.stronger == "" {
+				return
+			}
+			weaker := domain.AwaySide
+			if tt.stronger == domain.AwaySide {
+				weaker = domain.HomeSide
+			}
+			if goals[tt.stronger] <= goals[weaker] {
+				t.Errorf("full side scored %d against %d over 20 matches", goals[tt.stronger], goals[weaker])
+			}
+		})
+	}
+}
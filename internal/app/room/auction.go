@@ -0,0 +1,13 @@
+package room
+
+import (
+	"context"
+	"github.com/yourusername/TouchlineTactics/internal/app/auction"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// HandleStartAuction moves the host's room into the AUCTION phase. Fields
+// left empty in the payload fall back to the room settings.
+func (h *RoomEventHandler) HandleStartAuction(ctx context.Context, client ClientConn, payload auction.StartAuctionPayload) error {
+	return h.requestTransition(ctx, client, domain.RoomAuction, TransitionOptions{Auction: &payload})
+}
@@ -0,0 +1,121 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
+)
+
+// DefaultAutoStartDelay is the auto-start countdown when the room does not
+// set AutoStartDelay.
+const DefaultAutoStartDelay = 10 * time.Second
+
+type ForceStartPayload struct{}
+
+// AutoStartCountdown is the payload of an autoStartCountdown message. An
+// inactive countdown was cancelled, with Reason saying why if the start
+// itself failed.
+type AutoStartCountdown struct {
+	Active   bool       `json:"active"`
+	StartsAt *time.Time `json:"startsAt,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+}
+
+// countdowns holds the pending auto-start timer of each room.
+type countdowns struct {
+	timers map[string]*time.Timer // roomID -> pending start
+	mutex  sync.Mutex
+}
+
+// start schedules fire unless the room already has a countdown running.
+func (c *countdowns) start(roomID string, delay time.Duration, fire func()) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, running := c.timers[roomID]; running {
+		return false
+	}
+	if c.timers == nil {
+		c.timers = make(map[string]*time.Timer)
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		c.mutex.Lock()
+		current := c.timers[roomID] == timer
+		if current {
+			delete(c.timers, roomID)
+		}
+		c.mutex.Unlock()
+		if current {
+			fire()
+		}
+	})
+	c.timers[roomID] = timer
+	return true
+}
+
+// cancel stops the room's countdown, reporting whether one was running.
+func (c *countdowns) cancel(roomID string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	timer, running := c.timers[roomID]
+	if !running {
+		return false
+	}
+	timer.Stop()
+	delete(c.timers, roomID)
+	return true
+}
+
+// checkAutoStart starts the countdown once an auto-start room could enter
+// the auction, and cancels it as soon as it no longer could, e.g. because a
+// manager un-readied, joined or left.
+func (h *RoomEventHandler) checkAutoStart(ctx context.Context, roomID string) {
+	room, err := h.Store.GetRoom(ctx, roomID)
+	if err != nil {
+		h.countdowns.cancel(roomID)
+		if !errors.Is(err, domain.ErrNotFound) {
+			logger.Error(fmt.Sprintf("checking auto-start of room %s: %v", roomID, err))
+		}
+		return
+	}
+	room.Mutex.RLock()
+	startable := room.Settings.AutoStart && room.Status == domain.RoomWaiting
+	if startable {
+		reason, err := h.guard(ctx, room, domain.RoomWaiting, domain.RoomAuction, false)
+		startable = err == nil && reason == ""
+	}
+	delay := time.Duration(room.Settings.AutoStartDelay) * time.Second
+	room.Mutex.RUnlock()
+	if delay <= 0 {
+		delay = DefaultAutoStartDelay
+	}
+
+	if !startable {
+		if h.countdowns.cancel(roomID) {
+			h.Broadcast(roomID, EventAutoStart, AutoStartCountdown{})
+		}
+		return
+	}
+	startsAt := time.Now().Add(delay)
+	if h.countdowns.start(roomID, delay, func() { h.autoStart(roomID) }) {
+		h.Broadcast(roomID, EventAutoStart, AutoStartCountdown{Active: true, StartsAt: &startsAt})
+	}
+}
+
+// autoStart ends a countdown by moving the room into the auction.
+func (h *RoomEventHandler) autoStart(roomID string) {
+	if err := h.Transition(context.Background(), roomID, domain.RoomAuction, TransitionOptions{}); err != nil {
+		h.Broadcast(roomID, EventAutoStart, AutoStartCountdown{Reason: err.Error()})
+	}
+}
+
+// HandleForceStart lets the host start the auction now, cutting any
+// countdown short and without waiting for every manager to be ready.
+func (h *RoomEventHandler) HandleForceStart(ctx context.Context, client ClientConn, payload ForceStartPayload) error {
+	return h.requestTransition(ctx, client, domain.RoomAuction, TransitionOptions{Force: true})
+}
@@ -1,11 +1,49 @@
 package room
 
-import "github.com/yourusername/TouchlineTactics/internal/domain"
+import (
+	"sort"
 
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+const DefaultMaxSpectators = 10
+
+// IsRoomAtCapacity reports whether every manager seat is taken; spectators
+// do not count.
 func IsRoomAtCapacity(room *domain.Room) bool {
-	max := room.Settings.MaxUsers
+	return len(managerIDs(room)) >= maxManagers(room)
+}
+
+func maxManagers(room *domain.Room) int {
+	if room.Settings.MaxUsers == 0 {
+		return 4 // default max users
+	}
+	return room.Settings.MaxUsers
+}
+
+func IsSpectatorLimitReached(room *domain.Room) bool {
+	max := room.Settings.MaxSpectators
 	if max == 0 {
-		max = 4 // default max users
+		max = DefaultMaxSpectators
+	}
+	return len(room.Users)-len(managerIDs(room)) >= max
+}
+
+// managerIDs lists the room's managers in ID order. Callers must hold at
+// least a read lock on room.Mutex if the room is shared.
+func managerIDs(room *domain.Room) []string {
+	ids := make([]string, 0, len(room.Users))
+	for id, u := range room.Users {
+		if !u.IsSpectator() {
+			ids = append(ids, id)
+		}
 	}
-	return len(room.Users) >= max
+	sort.Strings(ids)
+	return ids
+}
+
+// isManager reports whether userID holds a manager seat in the room.
+func isManager(room *domain.Room, userID string) bool {
+	u, ok := room.Users[userID]
+	return ok && !u.IsSpectator()
 }
@@ -0,0 +1,354 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yourusername/TouchlineTactics/internal/app/auction"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
+)
+
+const (
+	DefaultChatPageSize = 50
+	MaxChatPageSize     = 100
+
+	// MaxReactions caps the distinct emoji on one message; MaxEmojiLength
+	// is in bytes.
+	MaxReactions   = 20
+	MaxEmojiLength = 32
+)
+
+var (
+	ErrInvalidChatCursor = errors.New("invalid chat cursor")
+	ErrRecipientOffline  = errors.New("that user is not connected")
+	ErrInvalidEmoji      = errors.New("invalid emoji")
+	ErrTooManyReactions  = errors.New("message has too many different reactions")
+	ErrMessageNotFound   = domain.ErrChatMessageNotFound
+)
+
+// mentionPattern matches @username; names are compared case-insensitively.
+var mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_.\-]+)`)
+
+type ChatMessagePayload struct {
+	Message string `json:"message"`
+}
+
+// WhisperPayload sends a private message to another member of the room.
+type WhisperPayload struct {
+	To      string `json:"to"` // userID
+	Message string `json:"message"`
+}
+
+// ReactMessagePayload adds or, with Remove, takes back the caller's emoji
+// reaction on a room message.
+type ReactMessagePayload struct {
+	MessageID int64  `json:"messageId"`
+	Emoji     string `json:"emoji"`
+	Remove    bool   `json:"remove,omitempty"`
+}
+
+// ChatReaction is the payload of a chatReaction message: the message's
+// reactions after a change.
+type ChatReaction struct {
+	MessageID int64               `json:"messageId"`
+	Reactions map[string][]string `json:"reactions"`
+}
+
+// Mention is sent to a user named with @ in a room message.
+type Mention struct {
+	MessageID int64  `json:"messageId"`
+	From      string `json:"from"`
+	FromID    string `json:"fromId"`
+	Message   string `json:"message"`
+}
+
+// GetChatHistoryPayload pages back through a room's chat. Before and
+// BeforeTime return messages older than that message ID or time; with
+// neither the latest messages are returned.
+type GetChatHistoryPayload struct {
+	Before     int64      `json:"before,omitempty"`
+	BeforeTime *time.Time `json:"beforeTime,omitempty"`
+	Limit      int        `json:"limit,omitempty"`
+}
+
+// ChatHistoryPage is a page of chat, oldest first. NextBefore is the cursor
+// for the page before it, or 0 at the start of the kept history.
+type ChatHistoryPage struct {
+	Messages   []domain.ChatMessage `json:"messages"`
+	NextBefore int64                `json:"nextBefore,omitempty"`
+}
+
+func (h *RoomEventHandler) HandleChatMessage(ctx context.Context, client ClientConn, payload ChatMessagePayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	room, err := h.Store.GetRoom(ctx, user.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	if err := canChat(user, room); err != nil {
+		return err
+	}
+	text, err := h.filterChat(user, room, payload.Message)
+	if err != nil {
+		return err
+	}
+	msg := domain.ChatMessage{
+		UserID:    user.ID.String(),
+		Username:  user.Username,
+		Message:   text,
+		Timestamp: time.Now(),
+		Mentions:  mentions(room, text, user.ID.String()),
+	}
+	if err := h.Store.AppendChatMessage(ctx, room.ID, &msg); err != nil {
+		return err
+	}
+	h.Broadcast(room.ID, EventChatMessage, msg)
+	for _, id := range msg.Mentions {
+		if conn, ok := h.sessions.conn(id); ok {
+			conn.Send(mustMarshal(map[string]interface{}{
+				"type":    EventMention,
+				"payload": Mention{MessageID: msg.ID, From: user.Username, FromID: msg.UserID, Message: msg.Message},
+			}))
+		}
+	}
+	return nil
+}
+
+// canChat checks the room lets the user post: spectators only when the host
+// allows it, and nobody while muted.
+func canChat(user *domain.User, room *domain.Room) error {
+	if user.IsSpectator() && !room.Settings.SpectatorChat {
+		return ErrSpectatorChat
+	}
+	room.Mutex.RLock()
+	muted := room.Moderation.IsMuted(user.ID.String(), time.Now())
+	room.Mutex.RUnlock()
+	if muted {
+		return ErrMuted
+	}
+	return nil
+}
+
+// mentions returns the IDs of the room members named with @ in text,
+// leaving out the sender.
+func mentions(room *domain.Room, text, senderID string) []string {
+	names := mentionPattern.FindAllStringSubmatch(text, -1)
+	if len(names) == 0 {
+		return nil
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, m := range names {
+		wanted[strings.ToLower(m[1])] = true
+	}
+	room.Mutex.RLock()
+	defer room.Mutex.RUnlock()
+	var ids []string
+	for id, u := range room.Users {
+		if id != senderID && wanted[strings.ToLower(u.Username)] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// HandleWhisper delivers a private message to one connected member of the
+// caller's room and echoes it back to the caller. Whispers are not kept in
+// the chat history.
+func (h *RoomEventHandler) HandleWhisper(ctx context.Context, client ClientConn, payload WhisperPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	room, err := h.Store.GetRoom(ctx, user.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	if err := canChat(user, room); err != nil {
+		return err
+	}
+	room.Mutex.RLock()
+	_, seated := room.Users[payload.To]
+	room.Mutex.RUnlock()
+	if !seated || payload.To == user.ID.String() {
+		return ErrUserNotFound
+	}
+	conn, ok := h.sessions.conn(payload.To)
+	if !ok {
+		return ErrRecipientOffline
+	}
+	text, err := h.filterChat(user, room, payload.Message)
+	if err != nil {
+		return err
+	}
+	msg := mustMarshal(map[string]interface{}{
+		"type": EventWhisper,
+		"payload": domain.ChatMessage{
+			Kind:      domain.ChatWhisper,
+			UserID:    user.ID.String(),
+			Username:  user.Username,
+			Message:   text,
+			Timestamp: time.Now(),
+			To:        payload.To,
+		},
+	})
+	conn.Send(msg)
+	client.Send(msg)
+	return nil
+}
+
+// HandleReactMessage toggles the caller's emoji on a room message and tells
+// the room the message's new reactions.
+func (h *RoomEventHandler) HandleReactMessage(ctx context.Context, client ClientConn, payload ReactMessagePayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	room, err := h.Store.GetRoom(ctx, user.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	if err := canChat(user, room); err != nil {
+		return err
+	}
+	if !validEmoji(payload.Emoji) {
+		return ErrInvalidEmoji
+	}
+	userID := user.ID.String()
+	msg, err := h.Store.UpdateChatMessage(ctx, room.ID, payload.MessageID, func(msg *domain.ChatMessage) error {
+		return react(msg, payload.Emoji, userID, !payload.Remove)
+	})
+	if err != nil {
+		return err
+	}
+	reactions := msg.Reactions
+	if reactions == nil {
+		reactions = map[string][]string{}
+	}
+	h.Broadcast(room.ID, EventChatReaction, ChatReaction{MessageID: msg.ID, Reactions: reactions})
+	return nil
+}
+
+func validEmoji(emoji string) bool {
+	if emoji == "" || len(emoji) > MaxEmojiLength {
+		return false
+	}
+	return strings.IndexFunc(emoji, unicode.IsSpace) < 0
+}
+
+// react adds or removes userID under emoji, dropping emoji nobody is left
+// reacting with.
+func react(msg *domain.ChatMessage, emoji, userID string, add bool) error {
+	users := msg.Reactions[emoji]
+	i := indexOf(users, userID)
+	switch {
+	case add && i >= 0, !add && i < 0:
+		return nil
+	case add:
+		if users == nil && len(msg.Reactions) >= MaxReactions {
+			return ErrTooManyReactions
+		}
+		if msg.Reactions == nil {
+			msg.Reactions = make(map[string][]string)
+		}
+		msg.Reactions[emoji] = append(users, userID)
+	default:
+		users = append(users[:i:i], users[i+1:]...)
+		if len(users) == 0 {
+			delete(msg.Reactions, emoji)
+		} else {
+			msg.Reactions[emoji] = users
+		}
+	}
+	return nil
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// PostSystemMessage adds a server message to the room's chat.
+func (h *RoomEventHandler) PostSystemMessage(ctx context.Context, roomID, text string) error {
+	msg := domain.ChatMessage{Kind: domain.ChatSystem, Message: text, Timestamp: time.Now()}
+	if err := h.Store.AppendChatMessage(ctx, roomID, &msg); err != nil {
+		return err
+	}
+	h.Broadcast(roomID, EventChatMessage, msg)
+	return nil
+}
+
+// HandlePlayerSold posts the outcome of each bid window to the room's chat.
+// It is wired up as the auction service's OnSold.
+func (h *RoomEventHandler) HandlePlayerSold(roomID string, sale auction.Sale) {
+	ctx := context.Background()
+	lines := []string{fmt.Sprintf("%s went unsold", sale.Player.Name)}
+	if sale.Winner != "" {
+		winner := sale.Winner
+		if room, err := h.Store.GetRoom(ctx, roomID); err == nil {
+			room.Mutex.RLock()
+			if u, ok := room.Users[sale.Winner]; ok {
+				winner = u.Username
+			}
+			room.Mutex.RUnlock()
+		}
+		lines = []string{fmt.Sprintf("%s bought %s for %d", winner, sale.Player.Name, sale.Bid)}
+		if sale.BudgetExhausted {
+			lines = append(lines, fmt.Sprintf("%s has exhausted their budget", winner))
+		}
+		if sale.SquadFull {
+			lines = append(lines, fmt.Sprintf("%s's squad is full", winner))
+		}
+	}
+	for _, line := range lines {
+		if err := h.PostSystemMessage(ctx, roomID, line); err != nil {
+			logger.Error(fmt.Sprintf("posting to room %s: %v", roomID, err))
+			return
+		}
+	}
+}
+
+func (h *RoomEventHandler) HandleGetChatHistory(ctx context.Context, client ClientConn, payload GetChatHistoryPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	if payload.Before < 0 || payload.Limit < 0 {
+		return ErrInvalidChatCursor
+	}
+	limit := payload.Limit
+	if limit == 0 {
+		limit = DefaultChatPageSize
+	}
+	if limit > MaxChatPageSize {
+		limit = MaxChatPageSize
+	}
+	cursor := domain.ChatCursor{BeforeID: payload.Before}
+	if payload.BeforeTime != nil {
+		cursor.BeforeTime = *payload.BeforeTime
+	}
+	messages, more, err := h.Store.GetChatHistory(ctx, user.RoomID, cursor, limit)
+	if err != nil {
+		return err
+	}
+	page := ChatHistoryPage{Messages: messages}
+	if more && len(messages) > 0 {
+		page.NextBefore = messages[0].ID
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventGetChatHistory,
+		"payload": page,
+	}))
+	return nil
+}
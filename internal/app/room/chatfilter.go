@@ -0,0 +1,160 @@
+package room
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+const (
+	DefaultMaxChatLength = 500
+	// DefaultChatRate and DefaultChatBurst bound how fast one user may post
+	// in one room.
+	DefaultChatRate  = 1
+	DefaultChatBurst = 5
+)
+
+var (
+	ErrEmptyMessage   = errors.New("message is empty")
+	ErrMessageTooLong = errors.New("message is too long")
+	ErrChatFlood      = errors.New("you are sending messages too quickly")
+	ErrLinksBlocked   = errors.New("links are not allowed in this room")
+	ErrChatDisabled   = errors.New("chat is turned off during the auction")
+)
+
+// ChatDraft is a message on its way through the chat filters. Filters may
+// rewrite Text.
+type ChatDraft struct {
+	User *domain.User
+	Room *domain.Room
+	Text string
+	Now  time.Time
+}
+
+// ChatFilter checks a draft before it is posted; an error rejects the
+// message and is sent back to its sender alone.
+type ChatFilter func(draft *ChatDraft) error
+
+// DefaultChatFilters is the standard chain: the auction toggle, length and
+// flood limits, link blocking and masking of words.
+func DefaultChatFilters(words []string) []ChatFilter {
+	return []ChatFilter{
+		AuctionChatToggle(),
+		MaxLength(DefaultMaxChatLength),
+		FloodControl(DefaultChatRate, DefaultChatBurst),
+		BlockLinks(),
+		MaskWords(words),
+	}
+}
+
+// filterChat runs the handler's chat filters over text.
+func (h *RoomEventHandler) filterChat(user *domain.User, room *domain.Room, text string) (string, error) {
+	draft := &ChatDraft{User: user, Room: room, Text: text, Now: time.Now()}
+	for _, filter := range h.ChatFilters {
+		if err := filter(draft); err != nil {
+			return "", err
+		}
+	}
+	return draft.Text, nil
+}
+
+// AuctionChatToggle rejects messages while the room is in the auction if
+// the host has set DisableAuctionChat.
+func AuctionChatToggle() ChatFilter {
+	return func(draft *ChatDraft) error {
+		draft.Room.Mutex.RLock()
+		off := draft.Room.Settings.DisableAuctionChat && draft.Room.Status == domain.RoomAuction
+		draft.Room.Mutex.RUnlock()
+		if off {
+			return ErrChatDisabled
+		}
+		return nil
+	}
+}
+
+// MaxLength trims surrounding space and rejects empty messages and those
+// longer than max characters.
+func MaxLength(max int) ChatFilter {
+	return func(draft *ChatDraft) error {
+		draft.Text = strings.TrimSpace(draft.Text)
+		switch n := utf8.RuneCountInString(draft.Text); {
+		case n == 0:
+			return ErrEmptyMessage
+		case n > max:
+			return fmt.Errorf("%w: at most %d characters", ErrMessageTooLong, max)
+		}
+		return nil
+	}
+}
+
+// FloodControl gives each user a token bucket per room, refilled at
+// perSecond and holding at most burst messages.
+func FloodControl(perSecond float64, burst int) ChatFilter {
+	limiter := &rateLimiter{perSecond: perSecond, burst: float64(burst), buckets: make(map[string]*bucket)}
+	return func(draft *ChatDraft) error {
+		if !limiter.allow(draft.Room.ID+":"+draft.User.ID.String(), draft.Now) {
+			return ErrChatFlood
+		}
+		return nil
+	}
+}
+
+// linkPattern matches URLs with a scheme, www. hosts and bare domains with
+// a common top-level domain.
+var linkPattern = regexp.MustCompile(`(?i)\b(?:[a-z][a-z0-9+.-]*://\S+|www\.\S+|[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:com|net|org|io|co|gg|me|tv|ly|xyz|info|app|dev|uk|de)\b)`)
+
+// BlockLinks rejects messages containing links unless the room has
+// AllowLinks set.
+func BlockLinks() ChatFilter {
+	return func(draft *ChatDraft) error {
+		draft.Room.Mutex.RLock()
+		allowed := draft.Room.Settings.AllowLinks
+		draft.Room.Mutex.RUnlock()
+		if !allowed && linkPattern.MatchString(draft.Text) {
+			return ErrLinksBlocked
+		}
+		return nil
+	}
+}
+
+// MaskWords replaces whole-word, case-insensitive matches of words with
+// asterisks. It does nothing for an empty list.
+func MaskWords(words []string) ChatFilter {
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) == 0 {
+		return func(*ChatDraft) error { return nil }
+	}
+	pattern := regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	return func(draft *ChatDraft) error {
+		draft.Text = pattern.ReplaceAllStringFunc(draft.Text, func(w string) string {
+			return strings.Repeat("*", utf8.RuneCountInString(w))
+		})
+		return nil
+	}
+}
+
+// ReadWordList reads one word per line, skipping blank lines and lines
+// starting with #.
+func ReadWordList(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words, scanner.Err()
+}
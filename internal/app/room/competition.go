@@ -0,0 +1,40 @@
+package room
+
+import (
+	"context"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+type StartCompetitionPayload struct {
+	Format domain.CompetitionFormat `json:"format"`
+	Legs   int                      `json:"legs,omitempty"` // league only, defaults to 1
+	Seed   int64                    `json:"seed,omitempty"`
+}
+
+type GetCompetitionPayload struct{}
+
+// HandleStartCompetition lets the host start a league or knockout among the
+// room's managers. The room moves to COMPETITION while it runs and to
+// FINISHED once a champion is known.
+func (h *RoomEventHandler) HandleStartCompetition(ctx context.Context, client ClientConn, payload StartCompetitionPayload) error {
+	return h.requestTransition(ctx, client, domain.RoomCompetition, TransitionOptions{Competition: &payload})
+}
+
+func (h *RoomEventHandler) HandleGetCompetition(ctx context.Context, client ClientConn, payload GetCompetitionPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	if h.Competitions == nil {
+		return ErrUnavailable
+	}
+	comp, ok := h.Competitions.Get(user.RoomID)
+	if !ok {
+		return ErrNoCompetition
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventGetCompetition,
+		"payload": comp,
+	}))
+	return nil
+}
@@ -1,10 +1,11 @@
 package room
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 
 	"github.com/yourusername/TouchlineTactics/internal/app/auction"
-	"github.com/yourusername/TouchlineTactics/internal/domain"
 )
 
 type ClientConn interface {
@@ -15,82 +16,120 @@ type ClientConn interface {
 type IncomingEvent struct {
 	Type    string          `json:"type"`
 	Payload json.RawMessage `json:"payload"`
+	// RequestID is echoed back in the ack or error frame for this message.
+	RequestID string `json:"requestId,omitempty"`
 }
 
 type EventDispatcher struct {
-	Handler *RoomEventHandler
+	Handler  *RoomEventHandler
+	Commands *Registry
 }
 
+// NewEventDispatcher registers the room, lineup, match and auction commands
+// behind panic recovery, logging and per-connection rate limiting. More
+// commands can be added to Commands before serving.
 func NewEventDispatcher(handler *RoomEventHandler) *EventDispatcher {
-	return &EventDispatcher{Handler: handler}
+	commands := NewRegistry()
+	commands.Use(Recover(), Logging(), RateLimit(DefaultRateLimit, DefaultRateBurst))
+	handler.RegisterCommands(commands)
+	return &EventDispatcher{Handler: handler, Commands: commands}
 }
 
-func (d *EventDispatcher) Dispatch(client ClientConn, message []byte) {
+// RegisterCommands adds the handler's websocket commands to r.
+func (h *RoomEventHandler) RegisterCommands(r *Registry) {
+	auth := Authenticated(h.Store)
+	member := RoomMember(h.Store)
+	manager := ManagerOnly(h.Store)
+	host := HostOnly(h.Store)
+	moderator := ModeratorOnly(h.Store)
+
+	r.Handle(EventCreateRoom, Typed(h.HandleCreateRoom))
+	r.Handle(EventJoinRoom, Typed(h.HandleJoinRoom))
+	r.Handle(EventListRooms, Typed(h.HandleListRooms))
+	r.Handle(EventUnsubscribeLobby, Typed(h.HandleUnsubscribeLobby))
+	r.Handle(EventLeaveRoom, Typed(h.HandleLeaveRoom), auth)
+	r.Handle(EventSetSettings, Typed(h.HandleSetSettings), host)
+	r.Handle(EventTransferHost, Typed(h.HandleTransferHost), host)
+	r.Handle(EventKickUser, Typed(h.HandleKickUser), moderator)
+	r.Handle(EventBanUser, Typed(h.HandleBanUser), moderator)
+	r.Handle(EventUnbanUser, Typed(h.HandleUnbanUser), moderator)
+	r.Handle(EventMuteUser, Typed(h.HandleMuteUser), moderator)
+	r.Handle(EventUnmuteUser, Typed(h.HandleUnmuteUser), moderator)
+	r.Handle(EventSetCoHost, Typed(h.HandleSetCoHost), host)
+	r.Handle(EventModerationLog, Typed(h.HandleGetModerationLog), moderator)
+	r.Handle(EventStartPhase, Typed(h.HandleStartPhase), host)
+	r.Handle(EventForceStart, Typed(h.HandleForceStart), host)
+	r.Handle(EventChatMessage, Typed(h.HandleChatMessage), member)
+	r.Handle(EventWhisper, Typed(h.HandleWhisper), member)
+	r.Handle(EventReactMessage, Typed(h.HandleReactMessage), member)
+	r.Handle(EventSetReady, Typed(h.HandleSetReady), manager)
+	r.Handle(EventGetChatHistory, Typed(h.HandleGetChatHistory), member)
+	r.Handle(EventGetRoomAnalytics, Typed(h.HandleGetRoomAnalytics), member)
+	r.Handle(EventCreateInvite, Typed(h.HandleCreateInvite), host)
+	r.Handle(EventRevokeInvite, Typed(h.HandleRevokeInvite), host)
+	r.Handle(EventListInvites, Typed(h.HandleListInvites), host)
+
+	r.Handle(EventSearchPlayers, Typed(h.HandleSearchPlayers))
+	r.Handle(EventSetLineup, Typed(h.HandleSetLineup), manager)
+	r.Handle(EventGetLineup, Typed(h.HandleGetLineup), member)
+	r.Handle(EventTeamRating, Typed(h.HandleTeamRating), member)
+	r.Handle(EventStartMatch, Typed(h.HandleStartMatch), host)
+	r.Handle(EventStartCompetition, Typed(h.HandleStartCompetition), host)
+	r.Handle(EventGetCompetition, Typed(h.HandleGetCompetition), member)
+
+	r.Handle(EventStartAuction, Typed(h.HandleStartAuction), host)
+	r.Handle(EventPlaceBid, h.handlePlaceBid, manager)
+}
+
+// handlePlaceBid bids as the caller in their own room, whatever the payload
+// claims.
+func (h *RoomEventHandler) handlePlaceBid(cmd *Command) error {
+	var payload auction.PlaceBidPayload
+	if err := decode(cmd.Payload, &payload); err != nil {
+		return err
+	}
+	if h.AuctionHandler == nil {
+		return ErrUnavailable
+	}
+	payload.RoomID = cmd.Room.ID
+	payload.UserID = cmd.User.ID.String()
+	return h.AuctionHandler.HandlePlaceBid(payload)
+}
+
+// Dispatch runs the handler for one inbound message and answers the sender
+// with an ack, or an error frame if the message could not be handled. ctx
+// should end when the client's connection does.
+func (d *EventDispatcher) Dispatch(ctx context.Context, client ClientConn, message []byte) {
 	var event IncomingEvent
 	if err := json.Unmarshal(message, &event); err != nil {
-		return // Optionally: send error to client
+		sendError(client, "", &HandlerError{Code: CodeBadRequest, Err: err})
+		return
 	}
-
-	switch event.Type {
-	case string(EventCreateRoom):
-		var payload CreateRoomPayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			d.Handler.HandleCreateRoom(client, payload)
-		}
-	case string(EventJoinRoom):
-		var payload JoinRoomPayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			d.Handler.HandleJoinRoom(client, payload)
-		}
-	case string(EventSetSettings):
-		var payload domain.RoomSettings
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			d.Handler.HandleSetSettings(client, payload)
-		}
-	case string(EventChatMessage):
-		var payload ChatMessagePayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			d.Handler.HandleChatMessage(client, payload)
-		}
-	case string(EventSetReady):
-		var payload SetReadyPayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			d.Handler.HandleSetReady(client, payload)
-		}
-	case string(EventGetChatHistory):
-		var payload GetChatHistoryPayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			d.Handler.HandleGetChatHistory(client, payload)
-		}
-	case string(EventGetRoomAnalytics):
-		var payload GetRoomAnalyticsPayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			d.Handler.HandleGetRoomAnalytics(client, payload)
-		}
-	case string(EventLeaveRoom):
-		var payload LeaveRoomPayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			d.Handler.HandleLeaveRoom(client, payload)
-		}
-	case string(EventKickUser):
-		var payload KickUserPayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			d.Handler.HandleKickUser(client, payload)
-		}
-	case "startAuction":
-		var payload auction.StartAuctionPayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			if d.Handler.AuctionHandler != nil {
-				d.Handler.AuctionHandler.HandleStartAuction(payload)
-			}
-		}
-	case "placeBid":
-		var payload auction.PlaceBidPayload
-		if err := json.Unmarshal(event.Payload, &payload); err == nil {
-			if d.Handler.AuctionHandler != nil {
-				d.Handler.AuctionHandler.HandlePlaceBid(payload)
-			}
-		}
-		// Add more cases for other events
+	handler, ok := d.Commands.Lookup(EventType(event.Type))
+	if !ok {
+		sendError(client, event.RequestID, &HandlerError{Code: CodeUnknownEvent, Err: fmt.Errorf("unknown event type %q", event.Type)})
+		return
 	}
+	err := handler(&Command{
+		Ctx:       ctx,
+		Client:    client,
+		Type:      EventType(event.Type),
+		Payload:   event.Payload,
+		RequestID: event.RequestID,
+	})
+	if err != nil {
+		sendError(client, event.RequestID, err)
+		return
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventAck,
+		"payload": AckFrame{RequestID: event.RequestID, Event: EventType(event.Type)},
+	}))
+}
+
+func sendError(client ClientConn, requestID string, err error) {
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventError,
+		"payload": newErrorFrame(err, requestID),
+	}))
 }
@@ -0,0 +1,146 @@
+package room
+
+import (
+	"errors"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// ErrorCode classifies a failed request in an error frame.
+type ErrorCode string
+
+const (
+	CodeBadRequest        ErrorCode = "bad_request"
+	CodeUnknownEvent      ErrorCode = "unknown_event"
+	CodeNotInRoom         ErrorCode = "not_in_room"
+	CodeNotFound          ErrorCode = "not_found"
+	CodeForbidden         ErrorCode = "forbidden"
+	CodeConflict          ErrorCode = "conflict"
+	CodeUnauthorized      ErrorCode = "unauthorized"
+	CodeInvalidTransition ErrorCode = "invalid_transition"
+	CodeInvalid           ErrorCode = "invalid"
+	CodeUnavailable       ErrorCode = "unavailable"
+	CodeInternal          ErrorCode = "internal"
+	CodeRateLimited       ErrorCode = "rate_limited"
+	CodeGone              ErrorCode = "gone"
+)
+
+var (
+	ErrRoomNotFound  = errors.New("room not found")
+	ErrNotHost       = errors.New("only the host can do that")
+	ErrNotInRoom     = errors.New("you are not in a room")
+	ErrUserNotFound  = errors.New("user not found")
+	ErrUnavailable   = errors.New("this feature is not available")
+	ErrUnknownPhase  = errors.New("unknown phase")
+	ErrSameManagers  = errors.New("a manager cannot play themselves")
+	ErrNoCompetition = errors.New("no competition in this room")
+	ErrSpectator     = errors.New("spectators cannot do that")
+	ErrSpectatorChat = errors.New("spectator chat is turned off in this room")
+)
+
+// errorCodes maps the package's sentinel errors to the code clients see.
+// Anything not listed is reported as CodeInvalid.
+var errorCodes = map[error]ErrorCode{
+	ErrRoomNotFound:          CodeNotFound,
+	ErrUserNotFound:          CodeNotFound,
+	ErrNoCompetition:         CodeNotFound,
+	ErrNotHost:               CodeForbidden,
+	ErrSpectator:             CodeForbidden,
+	ErrSpectatorChat:         CodeForbidden,
+	ErrNotModerator:          CodeForbidden,
+	ErrCannotModerate:        CodeForbidden,
+	ErrBanned:                CodeForbidden,
+	ErrMuted:                 CodeForbidden,
+	ErrSpectatorsFull:        CodeConflict,
+	ErrNotInRoom:             CodeNotInRoom,
+	ErrRoomExists:            CodeConflict,
+	ErrRoomStarted:           CodeConflict,
+	ErrInvalidUserID:         CodeUnauthorized,
+	ErrInvalidReconnectToken: CodeUnauthorized,
+	ErrSeatReleased:          CodeConflict,
+	ErrUnavailable:           CodeUnavailable,
+	ErrInviteNotFound:        CodeNotFound,
+	ErrInvalidRoomQuery:      CodeBadRequest,
+	ErrInvalidChatCursor:     CodeBadRequest,
+	ErrInvalidEmoji:          CodeBadRequest,
+	ErrTooManyReactions:      CodeConflict,
+	ErrRecipientOffline:      CodeUnavailable,
+	ErrMessageNotFound:       CodeNotFound,
+	ErrEmptyMessage:          CodeBadRequest,
+	ErrMessageTooLong:        CodeBadRequest,
+	ErrChatFlood:             CodeRateLimited,
+	ErrLinksBlocked:          CodeForbidden,
+	ErrChatDisabled:          CodeForbidden,
+	ErrWrongPassword:         CodeUnauthorized,
+	ErrTooManyAttempts:       CodeRateLimited,
+	ErrInviteExpired:         CodeGone,
+	ErrInviteRevoked:         CodeGone,
+	ErrInviteUsedUp:          CodeGone,
+	domain.ErrNotFound:       CodeNotFound,
+	domain.ErrConflict:       CodeConflict,
+	domain.ErrStorage:        CodeInternal,
+}
+
+// HandlerError attaches an explicit code to an error.
+type HandlerError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *HandlerError) Error() string { return e.Err.Error() }
+func (e *HandlerError) Unwrap() error { return e.Err }
+
+// ErrorFrame is the payload of an "error" message sent back to the client
+// whose request failed.
+type ErrorFrame struct {
+	Code      ErrorCode   `json:"code"`
+	Message   string      `json:"message"`
+	RequestID string      `json:"requestId,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// AckFrame is the payload of an "ack" message confirming a request succeeded.
+type AckFrame struct {
+	RequestID string    `json:"requestId,omitempty"`
+	Event     EventType `json:"event"`
+}
+
+func errorCode(err error) ErrorCode {
+	var handlerErr *HandlerError
+	if errors.As(err, &handlerErr) {
+		return handlerErr.Code
+	}
+	var transitionErr *TransitionError
+	if errors.As(err, &transitionErr) {
+		return CodeInvalidTransition
+	}
+	for sentinel, code := range errorCodes {
+		if errors.Is(err, sentinel) {
+			return code
+		}
+	}
+	return CodeInvalid
+}
+
+// missing reports a store's domain.ErrNotFound as the caller's more specific
+// err, passing other store failures through.
+func missing(storeErr, err error) error {
+	if errors.Is(storeErr, domain.ErrNotFound) {
+		return err
+	}
+	return storeErr
+}
+
+// newErrorFrame describes err to the client. Internal failures are logged by
+// the Logging middleware and not detailed to the client.
+func newErrorFrame(err error, requestID string) ErrorFrame {
+	frame := ErrorFrame{Code: errorCode(err), Message: err.Error(), RequestID: requestID}
+	if frame.Code == CodeInternal {
+		frame.Message = "internal error"
+	}
+	var transitionErr *TransitionError
+	if errors.As(err, &transitionErr) {
+		frame.Details = transitionErr
+	}
+	return frame
+}
@@ -0,0 +1,170 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
+)
+
+// DefaultEventLogSize is how many recent broadcasts are kept per room for
+// replay to reconnecting clients.
+const DefaultEventLogSize = 500
+
+// loggedEvent is a broadcast as it went out on the wire.
+type loggedEvent struct {
+	Seq     int64
+	Message []byte
+}
+
+// EventLog numbers each room's broadcasts and keeps the most recent ones so
+// a client that reconnects can catch up on what it missed.
+type EventLog struct {
+	Size  int
+	rooms map[string]*roomLog
+	mutex sync.Mutex
+
+	// Backend, when set, keeps the logs in place of this process's memory,
+	// so that every node numbers a room's events alike and a client can
+	// resume on any of them.
+	Backend EventBackend
+}
+
+// EventBackend keeps room event logs in shared storage; see
+// storage.RedisStore. Each stored message carries its "seq".
+type EventBackend interface {
+	// AppendEvent numbers a room event, encodes it with encode and keeps
+	// the latest keep of the room's events.
+	AppendEvent(ctx context.Context, roomID string, keep int, encode func(seq int64) ([]byte, error)) ([]byte, error)
+	// EventSeq is the number of the room's latest event.
+	EventSeq(ctx context.Context, roomID string) (int64, error)
+	// Events returns the room's kept events in the order they were stored.
+	Events(ctx context.Context, roomID string) ([][]byte, error)
+	ForgetEvents(ctx context.Context, roomID string) error
+}
+
+type roomLog struct {
+	seq    int64
+	events []loggedEvent
+}
+
+func NewEventLog(size int) *EventLog {
+	if size <= 0 {
+		size = DefaultEventLogSize
+	}
+	return &EventLog{Size: size, rooms: make(map[string]*roomLog)}
+}
+
+func encodeEvent(eventType EventType, data interface{}, seq int64) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":    eventType,
+		"payload": data,
+		"seq":     seq,
+	})
+}
+
+// MessageSeq reads the sequence number of a message built by Append; other
+// messages have none and read as 0.
+func MessageSeq(msg []byte) int64 {
+	var event struct {
+		Seq int64 `json:"seq"`
+	}
+	_ = json.Unmarshal(msg, &event)
+	return event.Seq
+}
+
+// Append assigns the event the room's next sequence number and returns the
+// message to send: {"type", "payload", "seq"}. The payload is encoded now so
+// later changes to data do not alter the replayed copy. If the backend
+// fails the message is sent unlogged, with seq 0.
+func (l *EventLog) Append(roomID string, eventType EventType, data interface{}) []byte {
+	if l.Backend != nil {
+		msg, err := l.Backend.AppendEvent(context.Background(), roomID, l.Size, func(seq int64) ([]byte, error) {
+			return encodeEvent(eventType, data, seq)
+		})
+		if err != nil {
+			logger.Error(fmt.Sprintf("logging %s in room %s: %v", eventType, roomID, err))
+			msg, _ = encodeEvent(eventType, data, 0)
+		}
+		return msg
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	log, ok := l.rooms[roomID]
+	if !ok {
+		log = &roomLog{}
+		l.rooms[roomID] = log
+	}
+	log.seq++
+	msg, _ := encodeEvent(eventType, data, log.seq)
+	log.events = append(log.events, loggedEvent{Seq: log.seq, Message: msg})
+	if len(log.events) > l.Size {
+		log.events = append([]loggedEvent(nil), log.events[len(log.events)-l.Size:]...)
+	}
+	return msg
+}
+
+// Seq is the sequence number of the room's latest event.
+func (l *EventLog) Seq(roomID string) int64 {
+	if l.Backend != nil {
+		seq, err := l.Backend.EventSeq(context.Background(), roomID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("reading the event seq of room %s: %v", roomID, err))
+		}
+		return seq
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if log, ok := l.rooms[roomID]; ok {
+		return log.seq
+	}
+	return 0
+}
+
+// Since returns the messages numbered after seq and up to through, oldest
+// first. complete is false when some of them have already been dropped
+// from the log.
+func (l *EventLog) Since(roomID string, seq, through int64) (messages [][]byte, complete bool) {
+	var events []loggedEvent
+	if l.Backend != nil {
+		stored, err := l.Backend.Events(context.Background(), roomID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("reading the event log of room %s: %v", roomID, err))
+			return nil, seq >= through
+		}
+		for _, msg := range stored {
+			events = append(events, loggedEvent{Seq: MessageSeq(msg), Message: msg})
+		}
+		// Nodes may store concurrent events out of order.
+		sort.Slice(events, func(i, j int) bool { return events[i].Seq < events[j].Seq })
+	} else {
+		l.mutex.Lock()
+		if log, ok := l.rooms[roomID]; ok {
+			events = log.events
+		}
+		l.mutex.Unlock()
+	}
+	complete = seq >= through || (len(events) > 0 && events[0].Seq <= seq+1)
+	for _, e := range events {
+		if e.Seq > seq && e.Seq <= through {
+			messages = append(messages, e.Message)
+		}
+	}
+	return messages, complete
+}
+
+// Forget drops a room's log once the room is gone.
+func (l *EventLog) Forget(roomID string) {
+	if l.Backend != nil {
+		if err := l.Backend.ForgetEvents(context.Background(), roomID); err != nil {
+			logger.Error(fmt.Sprintf("dropping the event log of room %s: %v", roomID, err))
+		}
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.rooms, roomID)
+}
@@ -1,10 +1,15 @@
 package room
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
 	"github.com/yourusername/TouchlineTactics/internal/app/auction"
+	"github.com/yourusername/TouchlineTactics/internal/app/competition"
+	"github.com/yourusername/TouchlineTactics/internal/app/lineup"
+	"github.com/yourusername/TouchlineTactics/internal/app/match"
+	appuser "github.com/yourusername/TouchlineTactics/internal/app/user"
 	"github.com/yourusername/TouchlineTactics/internal/domain"
 )
 
@@ -16,15 +21,48 @@ const (
 	EventLeaveRoom        EventType = "leaveRoom"
 	EventRoomStateUpdate  EventType = "roomStateUpdate"
 	EventChatMessage      EventType = "chatMessage"
+	EventWhisper          EventType = "whisper"
+	EventReactMessage     EventType = "reactMessage"
+	EventChatReaction     EventType = "chatReaction"
+	EventMention          EventType = "mention"
 	EventSetReady         EventType = "setReady"
+	EventForceStart       EventType = "forceStart"
+	EventAutoStart        EventType = "autoStartCountdown"
 	EventUserAction       EventType = "userAction"
 	EventKickUser         EventType = "kickUser"
+	EventBanUser          EventType = "banUser"
+	EventUnbanUser        EventType = "unbanUser"
+	EventMuteUser         EventType = "muteUser"
+	EventUnmuteUser       EventType = "unmuteUser"
+	EventSetCoHost        EventType = "setCoHost"
+	EventModerationLog    EventType = "moderationLog"
+	EventModeration       EventType = "moderation"
 	EventTransferHost     EventType = "transferHost"
 	EventStartPhase       EventType = "startPhase"
 	EventSetSettings      EventType = "setSettings"
 	EventListRooms        EventType = "listRooms"
+	EventUnsubscribeLobby EventType = "unsubscribeLobby"
+	EventLobbyUpdate      EventType = "lobbyUpdate"
 	EventGetChatHistory   EventType = "getChatHistory"
 	EventGetRoomAnalytics EventType = "getRoomAnalytics"
+	EventSearchPlayers    EventType = "searchPlayers"
+	EventPlayerPoolUpdate EventType = "playerPoolUpdate"
+	EventSetLineup        EventType = "setLineup"
+	EventGetLineup        EventType = "getLineup"
+	EventLineupUpdate     EventType = "lineupUpdate"
+	EventTeamRating       EventType = "teamRating"
+	EventStartMatch       EventType = "startMatch"
+	EventStartCompetition EventType = "startCompetition"
+	EventGetCompetition   EventType = "getCompetition"
+	EventStartAuction     EventType = "startAuction"
+	EventPlaceBid         EventType = "placeBid"
+	EventJoinedRoom       EventType = "joinedRoom"
+	EventCreateInvite     EventType = "createInvite"
+	EventRevokeInvite     EventType = "revokeInvite"
+	EventListInvites      EventType = "listInvites"
+	EventInvite           EventType = "invite"
+	EventAck              EventType = "ack"
+	EventError            EventType = "error"
 )
 
 type CreateRoomPayload struct {
@@ -40,6 +78,12 @@ type JoinRoomPayload struct {
 	Username       string `json:"username"`
 	Password       string `json:"password,omitempty"`
 	ReconnectToken string `json:"reconnectToken,omitempty"`
+	// LastSeq is the seq of the last event the client saw before it dropped.
+	LastSeq int64 `json:"lastSeq,omitempty"`
+	// Spectate joins as a watcher instead of taking a manager seat.
+	Spectate bool `json:"spectate,omitempty"`
+	// InviteCode admits the caller without the room password.
+	InviteCode string `json:"inviteCode,omitempty"`
 }
 
 type TransferHostPayload struct {
@@ -50,16 +94,10 @@ type StartPhasePayload struct {
 	Phase string `json:"phase"`
 }
 
-type ChatMessagePayload struct {
-	Message string `json:"message"`
-}
-
 type SetReadyPayload struct {
 	Ready bool `json:"ready"`
 }
 
-type GetChatHistoryPayload struct{}
-
 type GetRoomAnalyticsPayload struct{}
 
 type RoomAnalytics struct {
@@ -70,167 +108,229 @@ type RoomAnalytics struct {
 
 type LeaveRoomPayload struct{}
 
+type ListRoomsPayload struct {
+	RoomQuery
+	// Subscribe keeps the caller updated as matching rooms change.
+	Subscribe bool `json:"subscribe,omitempty"`
+}
+
+type UnsubscribeLobbyPayload struct{}
+
+// Store persists rooms, users and their data. Lookups of a missing record
+// return domain.ErrNotFound; backend failures wrap domain.ErrStorage.
+// SaveRoom bumps the room's version and returns domain.ErrConflict if the
+// room was saved since it was read; see updateRoom.
 type Store interface {
-	GetRoom(string) (*domain.Room, bool)
-	SaveRoom(*domain.Room)
-	DeleteRoom(string)
-	GetUser(string) (*domain.User, bool)
-	SaveUser(*domain.User)
-	DeleteUser(string)
-	ListRooms() []*domain.Room
+	GetRoom(ctx context.Context, id string) (*domain.Room, error)
+	SaveRoom(ctx context.Context, room *domain.Room) error
+	DeleteRoom(ctx context.Context, id string) error
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+	SaveUser(ctx context.Context, user *domain.User) error
+	DeleteUser(ctx context.Context, id string) error
+	ListRooms(ctx context.Context) ([]*domain.Room, error)
+	GetPlayerPool(ctx context.Context, roomID string) ([]domain.Player, error)
+	SavePlayerPool(ctx context.Context, roomID string, players []domain.Player) error
+	DeletePlayerPool(ctx context.Context, roomID string) error
+	GetTeam(ctx context.Context, roomID, userID string) ([]domain.Player, error)
+	AddPlayerToTeam(ctx context.Context, roomID, userID string, player domain.Player) error
+	GetLineup(ctx context.Context, roomID, userID string) (*domain.Lineup, error)
+	SaveLineup(ctx context.Context, lineup *domain.Lineup) error
+	GetInvite(ctx context.Context, code string) (*domain.Invite, error)
+	SaveInvite(ctx context.Context, invite *domain.Invite) error
+	DeleteInvite(ctx context.Context, code string) error
+	ListInvites(ctx context.Context, roomID string) ([]*domain.Invite, error)
+	// AddInviteUse adds delta to an invite's use count in one step and
+	// returns the new count.
+	AddInviteUse(ctx context.Context, code string, delta int) (int, error)
+	GetUserByReconnectToken(ctx context.Context, token string) (*domain.User, error)
+	// AppendChatMessage assigns msg the room's next message ID.
+	AppendChatMessage(ctx context.Context, roomID string, msg *domain.ChatMessage) error
+	// GetChatHistory returns up to limit of the latest messages the cursor
+	// admits, oldest first, and whether there are older ones.
+	GetChatHistory(ctx context.Context, roomID string, cursor domain.ChatCursor, limit int) ([]domain.ChatMessage, bool, error)
+	// UpdateChatMessage applies update to a kept message, returning
+	// domain.ErrChatMessageNotFound if there is none with that ID.
+	UpdateChatMessage(ctx context.Context, roomID string, id int64, update func(*domain.ChatMessage) error) (domain.ChatMessage, error)
 }
 
 type RoomEventHandler struct {
 	Store          Store
 	RoomService    *RoomService
+	UserService    *appuser.UserService
 	Broadcast      func(roomID string, eventType EventType, data interface{})
+	Subscribe      func(roomID string, client ClientConn) // moves an admitted connection onto the room's broadcasts
+	Unsubscribe    func(client ClientConn)                // takes a connection off its room's broadcasts
 	AuctionHandler *auction.AuctionEventHandler
+	Lineups        *lineup.LineupService
+	Matches        *match.MatchService
+	Competitions   *competition.CompetitionService
+	Events         *EventLog
+	// ReconnectGrace is how long a dropped user's seat is held; zero means
+	// DefaultReconnectGrace.
+	ReconnectGrace time.Duration
+	// InviteLinkBase is prefixed to invite codes to make shareable links;
+	// empty means DefaultInviteLinkBase.
+	InviteLinkBase string
+	// ChatFilters vet every chat message and whisper, in order; see
+	// DefaultChatFilters.
+	ChatFilters []ChatFilter
+	sessions    sessions
+	joinLockout joinLockout
+	lobby       lobby
+	countdowns  countdowns
 }
 
-// Method signatures for event handling
-func (h *RoomEventHandler) HandleCreateRoom(client ClientConn, payload CreateRoomPayload) {}
-func (h *RoomEventHandler) HandleJoinRoom(client ClientConn, payload JoinRoomPayload)     {}
-
-func (h *RoomEventHandler) HandleTransferHost(client ClientConn, payload TransferHostPayload) {
-	user, ok := h.Store.GetUser(client.ID())
-	if !ok {
-		return
+func (h *RoomEventHandler) HandleTransferHost(ctx context.Context, client ClientConn, payload TransferHostPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
 	}
-	room, ok := h.Store.GetRoom(user.RoomID)
-	if !ok {
-		return
+	room, err := h.updateRoom(ctx, user.RoomID, func(room *domain.Room) error {
+		if room.HostID != user.ID.String() {
+			return ErrNotHost
+		}
+		if _, ok := room.Users[payload.NewHostID]; !ok {
+			return ErrUserNotFound
+		}
+		if !isManager(room, payload.NewHostID) {
+			return ErrSpectator
+		}
+		room.HostID = payload.NewHostID
+		for _, u := range room.Users {
+			u.IsHost = (u.ID.String() == payload.NewHostID)
+			if u.IsHost {
+				u.IsCoHost = false
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	if room.HostID != user.ID.String() {
-		return // Only host can transfer
-	}
-	room.HostID = payload.NewHostID
-	for _, u := range room.Users {
-		u.IsHost = (u.ID.String() == payload.NewHostID)
-	}
-	h.Store.SaveRoom(room)
 	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+	h.roomChanged(ctx, room.ID)
+	return nil
 }
 
-func (h *RoomEventHandler) HandleStartPhase(client ClientConn, payload StartPhasePayload) {
-	user, ok := h.Store.GetUser(client.ID())
-	if !ok {
-		return
-	}
-	room, ok := h.Store.GetRoom(user.RoomID)
-	if !ok {
-		return
-	}
-	if room.HostID != user.ID.String() {
-		return // Only host can start phase
-	}
-	room.Status = domain.RoomStatus(payload.Phase)
-	h.Store.SaveRoom(room)
-	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+// HandleStartPhase asks for the room to move to another phase; see
+// Transition for which moves are allowed.
+func (h *RoomEventHandler) HandleStartPhase(ctx context.Context, client ClientConn, payload StartPhasePayload) error {
+	return h.requestTransition(ctx, client, domain.RoomStatus(payload.Phase), TransitionOptions{})
 }
 
-func (h *RoomEventHandler) HandleSetSettings(client ClientConn, payload domain.RoomSettings) {
-	user, ok := h.Store.GetUser(client.ID())
-	if !ok {
-		return
-	}
-	room, ok := h.Store.GetRoom(user.RoomID)
-	if !ok {
-		return
-	}
-	if room.HostID != user.ID.String() {
-		return // Only host can update settings
-	}
-	room.Settings = payload
-	h.Store.SaveRoom(room)
-	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+// SetSettingsPayload replaces the room settings. Password, when present,
+// replaces the room password; an empty string removes it.
+type SetSettingsPayload struct {
+	domain.RoomSettings
+	Password *string `json:"password,omitempty"`
 }
 
-func (h *RoomEventHandler) HandleChatMessage(client ClientConn, payload ChatMessagePayload) {
-	user, ok := h.Store.GetUser(client.ID())
-	if !ok {
-		return
+func (h *RoomEventHandler) HandleSetSettings(ctx context.Context, client ClientConn, payload SetSettingsPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
 	}
-	room, ok := h.Store.GetRoom(user.RoomID)
-	if !ok {
-		return
+	var hash string
+	if payload.Password != nil {
+		if hash, err = HashPassword(*payload.Password); err != nil {
+			return err
+		}
 	}
-	msg := domain.ChatMessage{
-		UserID:    user.ID.String(),
-		Username:  user.Username,
-		Message:   payload.Message,
-		Timestamp: time.Now(),
+	room, err := h.updateRoom(ctx, user.RoomID, func(room *domain.Room) error {
+		if room.HostID != user.ID.String() {
+			return ErrNotHost
+		}
+		settings := payload.RoomSettings
+		settings.PasswordHash = room.Settings.PasswordHash
+		if payload.Password != nil {
+			settings.PasswordHash = hash
+		}
+		room.Settings = settings
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	room.Mutex.Lock()
-	room.Chat = append(room.Chat, msg)
-	room.Mutex.Unlock()
-	h.Store.SaveRoom(room)
-	h.Broadcast(room.ID, EventChatMessage, msg)
+	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+	h.roomChanged(ctx, room.ID)
+	return nil
 }
 
-func (h *RoomEventHandler) HandleSetReady(client ClientConn, payload SetReadyPayload) {
-	user, ok := h.Store.GetUser(client.ID())
-	if !ok {
-		return
+func (h *RoomEventHandler) HandleSetReady(ctx context.Context, client ClientConn, payload SetReadyPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	if user.IsSpectator() {
+		return ErrSpectator
 	}
 	user.Ready = payload.Ready
-	h.Store.SaveUser(user)
-	room, ok := h.Store.GetRoom(user.RoomID)
-	if !ok {
-		return
+	if err := h.Store.SaveUser(ctx, user); err != nil {
+		return err
+	}
+	room, err := h.updateRoom(ctx, user.RoomID, func(room *domain.Room) error {
+		if seat, ok := room.Users[user.ID.String()]; ok {
+			seat.Ready = payload.Ready
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+	h.roomChanged(ctx, room.ID)
+	return nil
 }
 
-func (h *RoomEventHandler) HandleGetChatHistory(client ClientConn, payload GetChatHistoryPayload) {
-	user, ok := h.Store.GetUser(client.ID())
-	if !ok {
-		return
+func (h *RoomEventHandler) HandleGetRoomAnalytics(ctx context.Context, client ClientConn, payload GetRoomAnalyticsPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
 	}
-	room, ok := h.Store.GetRoom(user.RoomID)
-	if !ok {
-		return
+	room, err := h.Store.GetRoom(ctx, user.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
 	}
-	client.Send(mustMarshal(map[string]interface{}{
-		"type":    EventGetChatHistory,
-		"payload": room.Chat,
-	}))
-}
-
-func (h *RoomEventHandler) HandleGetRoomAnalytics(client ClientConn, payload GetRoomAnalyticsPayload) {
-	user, ok := h.Store.GetUser(client.ID())
-	if !ok {
-		return
+	// Message IDs count up from 1, so the latest ID is the number sent.
+	var totalMessages int
+	latest, _, err := h.Store.GetChatHistory(ctx, room.ID, domain.ChatCursor{}, 1)
+	if err != nil {
+		return err
 	}
-	room, ok := h.Store.GetRoom(user.RoomID)
-	if !ok {
-		return
+	if len(latest) > 0 {
+		totalMessages = int(latest[0].ID)
 	}
 	analytics := RoomAnalytics{
 		RoomID:        room.ID,
-		TotalMessages: len(room.Chat),
+		TotalMessages: totalMessages,
 		TotalUsers:    len(room.Users),
 	}
 	client.Send(mustMarshal(map[string]interface{}{
 		"type":    EventGetRoomAnalytics,
 		"payload": analytics,
 	}))
+	return nil
 }
 
-func (h *RoomEventHandler) HandleLeaveRoom(client ClientConn, payload LeaveRoomPayload) {
-	user, ok := h.Store.GetUser(client.ID())
-	if !ok {
-		return
+func (h *RoomEventHandler) HandleLeaveRoom(ctx context.Context, client ClientConn, payload LeaveRoomPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	h.sessions.forget(user.ID.String())
+	h.unsubscribe(client)
+	if err := h.LeaveRoom(ctx, user); err != nil {
+		return err
 	}
-	h.LeaveRoom(user)
-	h.Store.DeleteUser(user.ID.String())
+	return h.Store.DeleteUser(ctx, user.ID.String())
 }
 
-func (h *RoomEventHandler) HandleKickUser(client ClientConn, payload KickUserPayload) {
-	host, ok := h.Store.GetUser(client.ID())
-	if !ok {
-		return
+func (h *RoomEventHandler) HandleKickUser(ctx context.Context, client ClientConn, payload KickUserPayload) error {
+	actor, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
 	}
-	h.KickUser(host, payload)
+	return h.KickUser(ctx, actor, payload)
 }
 
 func mustMarshal(v interface{}) []byte {
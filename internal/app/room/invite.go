@@ -0,0 +1,253 @@
+package room
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
+)
+
+const (
+	InviteCodeLength = 8
+	// inviteAlphabet leaves out characters that are easy to misread: 0/O,
+	// 1/I/L.
+	inviteAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+	DefaultInviteLinkBase = "/join/"
+)
+
+var (
+	ErrInviteNotFound = errors.New("invite not found")
+	ErrInviteExpired  = errors.New("invite has expired")
+	ErrInviteRevoked  = errors.New("invite has been revoked")
+	ErrInviteUsedUp   = errors.New("invite has no uses left")
+)
+
+type CreateInvitePayload struct {
+	ExpiresIn int `json:"expiresIn,omitempty"` // seconds, 0 never expires
+	MaxUses   int `json:"maxUses,omitempty"`   // 0 is unlimited
+}
+
+type RevokeInvitePayload struct {
+	Code string `json:"code"`
+}
+
+type ListInvitesPayload struct{}
+
+// InviteView is an invite as shown to the host, with its shareable link.
+type InviteView struct {
+	*domain.Invite
+	Link string `json:"link"`
+}
+
+// InviteSummary is what anyone holding a code may learn about its room.
+type InviteSummary struct {
+	Code      string            `json:"code"`
+	RoomID    string            `json:"roomId"`
+	Status    domain.RoomStatus `json:"status"`
+	NumUsers  int               `json:"numUsers"`
+	MaxUsers  int               `json:"maxUsers"`
+	Private   bool              `json:"private"`
+	ExpiresAt *time.Time        `json:"expiresAt,omitempty"`
+}
+
+func NewInviteCode() string {
+	b := make([]byte, InviteCodeLength)
+	max := big.NewInt(int64(len(inviteAlphabet)))
+	for i := range b {
+		n, _ := rand.Int(rand.Reader, max)
+		b[i] = inviteAlphabet[n.Int64()]
+	}
+	return string(b)
+}
+
+// NormalizeInviteCode accepts codes typed in any case, with spaces or dashes.
+func NormalizeInviteCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// CreateInvite issues a new code for the host's room.
+func (h *RoomEventHandler) CreateInvite(ctx context.Context, hostID, roomID string, expiresIn time.Duration, maxUses int) (*domain.Invite, error) {
+	room, err := h.Store.GetRoom(ctx, roomID)
+	if err != nil {
+		return nil, missing(err, ErrRoomNotFound)
+	}
+	if room.HostID != hostID {
+		return nil, ErrNotHost
+	}
+	now := time.Now()
+	invite := &domain.Invite{
+		RoomID:    roomID,
+		CreatedBy: hostID,
+		CreatedAt: now,
+		MaxUses:   maxUses,
+	}
+	if expiresIn > 0 {
+		expires := now.Add(expiresIn)
+		invite.ExpiresAt = &expires
+	}
+	for {
+		invite.Code = NewInviteCode()
+		_, err := h.Store.GetInvite(ctx, invite.Code)
+		if errors.Is(err, domain.ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := h.Store.SaveInvite(ctx, invite); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// RevokeInvite stops a code from admitting anyone else.
+func (h *RoomEventHandler) RevokeInvite(ctx context.Context, hostID, code string) error {
+	invite, err := h.Store.GetInvite(ctx, NormalizeInviteCode(code))
+	if err != nil {
+		return missing(err, ErrInviteNotFound)
+	}
+	room, err := h.Store.GetRoom(ctx, invite.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	if room.HostID != hostID {
+		return ErrNotHost
+	}
+	invite.Revoked = true
+	return h.Store.SaveInvite(ctx, invite)
+}
+
+// ResolveInvite looks up a usable invite and its room.
+func (h *RoomEventHandler) ResolveInvite(ctx context.Context, code string) (*domain.Invite, *domain.Room, error) {
+	invite, err := h.Store.GetInvite(ctx, NormalizeInviteCode(code))
+	if err != nil {
+		return nil, nil, missing(err, ErrInviteNotFound)
+	}
+	switch {
+	case invite.Revoked:
+		return nil, nil, ErrInviteRevoked
+	case invite.ExpiresAt != nil && time.Now().After(*invite.ExpiresAt):
+		return nil, nil, ErrInviteExpired
+	case invite.MaxUses > 0 && invite.Uses >= invite.MaxUses:
+		return nil, nil, ErrInviteUsedUp
+	}
+	room, err := h.Store.GetRoom(ctx, invite.RoomID)
+	if err != nil {
+		return nil, nil, missing(err, ErrInviteNotFound)
+	}
+	return invite, room, nil
+}
+
+// InviteSummary describes the room behind a code without its password.
+func (h *RoomEventHandler) InviteSummary(ctx context.Context, code string) (InviteSummary, error) {
+	invite, room, err := h.ResolveInvite(ctx, code)
+	if err != nil {
+		return InviteSummary{}, err
+	}
+	room.Mutex.RLock()
+	defer room.Mutex.RUnlock()
+	return InviteSummary{
+		Code:      invite.Code,
+		RoomID:    room.ID,
+		Status:    room.Status,
+		NumUsers:  len(managerIDs(room)),
+		MaxUsers:  room.Settings.MaxUsers,
+		Private:   room.Settings.Private,
+		ExpiresAt: invite.ExpiresAt,
+	}, nil
+}
+
+// joinByInvite seats the user in the invite's room, skipping the password.
+// The use is counted first, in the store so that joins on other nodes see
+// it, and given back if the count went over MaxUses or the user could not
+// be seated.
+func (h *RoomEventHandler) joinByInvite(ctx context.Context, user *domain.User, roomID, code string) (*domain.Room, error) {
+	invite, room, err := h.ResolveInvite(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if roomID != "" && roomID != room.ID {
+		return nil, ErrInviteNotFound
+	}
+	uses, err := h.Store.AddInviteUse(ctx, invite.Code, 1)
+	if err != nil {
+		return nil, missing(err, ErrInviteNotFound)
+	}
+	release := func() {
+		if _, err := h.Store.AddInviteUse(ctx, invite.Code, -1); err != nil {
+			logger.Error(fmt.Sprintf("giving back a use of invite %s: %v", invite.Code, err))
+		}
+	}
+	if invite.MaxUses > 0 && uses > invite.MaxUses {
+		release()
+		return nil, ErrInviteUsedUp
+	}
+	if room, err = h.admit(ctx, user, room.ID); err != nil {
+		release()
+		return nil, err
+	}
+	return room, nil
+}
+
+func (h *RoomEventHandler) inviteView(invite *domain.Invite) InviteView {
+	base := h.InviteLinkBase
+	if base == "" {
+		base = DefaultInviteLinkBase
+	}
+	return InviteView{Invite: invite, Link: base + invite.Code}
+}
+
+func (h *RoomEventHandler) HandleCreateInvite(ctx context.Context, client ClientConn, payload CreateInvitePayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	invite, err := h.CreateInvite(ctx, user.ID.String(), user.RoomID, time.Duration(payload.ExpiresIn)*time.Second, payload.MaxUses)
+	if err != nil {
+		return err
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventInvite,
+		"payload": h.inviteView(invite),
+	}))
+	return nil
+}
+
+func (h *RoomEventHandler) HandleRevokeInvite(ctx context.Context, client ClientConn, payload RevokeInvitePayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	return h.RevokeInvite(ctx, user.ID.String(), payload.Code)
+}
+
+func (h *RoomEventHandler) HandleListInvites(ctx context.Context, client ClientConn, payload ListInvitesPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	invites, err := h.Store.ListInvites(ctx, user.RoomID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(invites, func(i, j int) bool { return invites[i].CreatedAt.Before(invites[j].CreatedAt) })
+	views := make([]InviteView, 0, len(invites))
+	for _, invite := range invites {
+		views = append(views, h.inviteView(invite))
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventListInvites,
+		"payload": views,
+	}))
+	return nil
+}
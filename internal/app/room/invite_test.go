@@ -0,0 +1,93 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/internal/storage"
+)
+
+func inviteRoom(t *testing.T, maxUses int) (*RoomEventHandler, *storage.MemoryStore) {
+	t.Helper()
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	room := &domain.Room{
+		ID:       "r1",
+		Status:   domain.RoomWaiting,
+		Settings: domain.RoomSettings{MaxUsers: 20},
+		Users:    map[string]*domain.User{},
+	}
+	if err := store.SaveRoom(ctx, room); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveInvite(ctx, &domain.Invite{Code: "CODE", RoomID: "r1", MaxUses: maxUses}); err != nil {
+		t.Fatal(err)
+	}
+	return &RoomEventHandler{Store: store}, store
+}
+
+func manager() *domain.User {
+	return &domain.User{ID: uuid.New(), Username: "m", RoomID: "r1", Role: domain.RoleManager}
+}
+
+func TestJoinByInviteStopsAtMaxUses(t *testing.T) {
+	ctx := context.Background()
+	h, store := inviteRoom(t, 3)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = h.joinByInvite(ctx, manager(), "r1", "CODE")
+		}(i)
+	}
+	wg.Wait()
+
+	joined := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			joined++
+		case !errors.Is(err, ErrInviteUsedUp):
+			t.Errorf("joining: %v", err)
+		}
+	}
+	if joined != 3 {
+		t.Errorf("%d joined with a 3-use invite", joined)
+	}
+	invite, _ := store.GetInvite(ctx, "CODE")
+	if invite.Uses != 3 {
+		t.Errorf("uses = %d, want 3", invite.Uses)
+	}
+	room, _ := store.GetRoom(ctx, "r1")
+	if len(room.Users) != 3 {
+		t.Errorf("%d seated, want 3", len(room.Users))
+	}
+}
+
+func TestJoinByInviteGivesBackTheUseWhenNotAdmitted(t *testing.T) {
+	ctx := context.Background()
+	h, store := inviteRoom(t, 1)
+	banned := manager()
+	room, _ := store.GetRoom(ctx, "r1")
+	room.Moderation.Bans = map[string]domain.Ban{banned.ID.String(): {UserID: banned.ID.String()}}
+
+	if _, err := h.joinByInvite(ctx, banned, "r1", "CODE"); !errors.Is(err, ErrBanned) {
+		t.Fatalf("joining banned: err = %v, want ErrBanned", err)
+	}
+	if invite, _ := store.GetInvite(ctx, "CODE"); invite.Uses != 0 {
+		t.Errorf("uses = %d after a refused join, want 0", invite.Uses)
+	}
+	if _, err := h.joinByInvite(ctx, manager(), "r1", "CODE"); err != nil {
+		t.Errorf("joining with the use given back: %v", err)
+	}
+	if _, err := h.joinByInvite(ctx, manager(), "r1", "CODE"); !errors.Is(err, ErrInviteUsedUp) {
+		t.Errorf("joining past MaxUses: err = %v, want ErrInviteUsedUp", err)
+	}
+}
@@ -1,21 +1,257 @@
 package room
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/yourusername/TouchlineTactics/internal/domain"
 )
 
-func (h *RoomEventHandler) JoinRoom(user *domain.User, room *domain.Room, password string) error {
-	if room.Settings.Private && room.Settings.Password != password {
-		return errors.New("invalid password or private room")
+var (
+	ErrRoomExists     = errors.New("room already exists")
+	ErrRoomStarted    = errors.New("room has already started")
+	ErrSpectatorsFull = errors.New("room has no spectator places left")
+	ErrMissingRoomID  = errors.New("roomId is required")
+	ErrMissingName    = errors.New("username is required")
+	ErrInvalidUserID  = errors.New("connection userId must be a UUID")
+	ErrInvalidSetting = errors.New("invalid room settings")
+	ErrWrongPassword  = errors.New("invalid password or private room")
+)
+
+// JoinedRoomPayload acknowledges a createRoom or joinRoom to the caller. The
+// reconnect token lets the client rejoin as the same user after a drop.
+type JoinedRoomPayload struct {
+	UserID         string       `json:"userId"`
+	ReconnectToken string       `json:"reconnectToken"`
+	Room           *domain.Room `json:"room"`
+	// Seq is the room's latest event sequence number at the time of the
+	// snapshot; resumed sessions are sent the events they missed after it.
+	Seq             int64 `json:"seq"`
+	Resumed         bool  `json:"resumed,omitempty"`
+	ReplayTruncated bool  `json:"replayTruncated,omitempty"`
+}
+
+func (h *RoomEventHandler) JoinRoom(ctx context.Context, user *domain.User, room *domain.Room, password string) (*domain.Room, error) {
+	if room.Settings.PasswordHash != "" && !VerifyPassword(room.Settings.PasswordHash, password) {
+		return nil, ErrWrongPassword
+	}
+	return h.admit(ctx, user, room.ID)
+}
+
+// admit seats the user if they are not banned and the room has space for
+// their role, returning the updated room. Managers are only seated while the
+// room is waiting; the status is checked again here as another node may have
+// started the room since the caller read it.
+func (h *RoomEventHandler) admit(ctx context.Context, user *domain.User, roomID string) (*domain.Room, error) {
+	return h.updateRoom(ctx, roomID, func(room *domain.Room) error {
+		if _, banned := room.Moderation.Bans[user.ID.String()]; banned {
+			return ErrBanned
+		}
+		if user.IsSpectator() {
+			if IsSpectatorLimitReached(room) {
+				return ErrSpectatorsFull
+			}
+		} else if room.Status != domain.RoomWaiting {
+			return ErrRoomStarted
+		} else if IsRoomAtCapacity(room) {
+			return errors.New("room is at capacity")
+		}
+		room.Users[user.ID.String()] = user.Seat()
+		room.LastActivity = time.Now()
+		return nil
+	})
+}
+
+// HandleCreateRoom creates a room with the caller as its host.
+func (h *RoomEventHandler) HandleCreateRoom(ctx context.Context, client ClientConn, payload CreateRoomPayload) error {
+	if payload.RoomID == "" {
+		return ErrMissingRoomID
+	}
+	switch _, err := h.Store.GetRoom(ctx, payload.RoomID); {
+	case err == nil:
+		return ErrRoomExists
+	case !errors.Is(err, domain.ErrNotFound):
+		return err
+	}
+	settings, err := roomSettings(payload)
+	if err != nil {
+		return err
+	}
+	user, err := h.newUser(client, payload.Username, payload.RoomID, true)
+	if err != nil {
+		return err
+	}
+	if err := h.leaveCurrentRoom(ctx, client, payload.RoomID); err != nil {
+		return err
+	}
+
+	room := h.RoomService.NewRoom(payload.RoomID, user.ID.String(), settings)
+	room.LastActivity = time.Now()
+	if err := h.RoomService.AddUser(room, user); err != nil {
+		return err
+	}
+	if err := h.Store.SaveRoom(ctx, room); errors.Is(err, domain.ErrConflict) {
+		// Another room by that ID was created since the check above.
+		return ErrRoomExists
+	} else if err != nil {
+		return err
+	}
+	if err := h.Store.SaveUser(ctx, user); err != nil {
+		return err
+	}
+	h.acknowledgeJoin(ctx, client, user, room)
+	return nil
+}
+
+// HandleJoinRoom adds the caller to an existing room, or resumes their seat
+// when a reconnect token is given. Managers can only join rooms that have
+// not started; spectators can join at any time.
+func (h *RoomEventHandler) HandleJoinRoom(ctx context.Context, client ClientConn, payload JoinRoomPayload) error {
+	if payload.ReconnectToken != "" {
+		return h.resumeSession(ctx, client, payload)
+	}
+	room, err := h.Store.GetRoom(ctx, payload.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	room.Mutex.RLock()
+	_, member := room.Users[client.ID()]
+	status := room.Status
+	room.Mutex.RUnlock()
+	if member {
+		// Already in the room: resend the snapshot.
+		user, err := h.Store.GetUser(ctx, client.ID())
+		switch {
+		case err == nil:
+			if user.Disconnected {
+				return ErrInvalidReconnectToken
+			}
+			h.acknowledgeJoin(ctx, client, user, room)
+			return nil
+		case !errors.Is(err, domain.ErrNotFound):
+			return err
+		}
+	}
+	if status != domain.RoomWaiting && !payload.Spectate {
+		return ErrRoomStarted
 	}
-	if IsRoomAtCapacity(room) {
-		return errors.New("room is at capacity")
+	user, err := h.newUser(client, payload.Username, payload.RoomID, false)
+	if err != nil {
+		return err
 	}
-	room.Mutex.Lock()
-	defer room.Mutex.Unlock()
-	room.Users[user.ID.String()] = user
-	h.Store.SaveRoom(room)
+	if payload.Spectate {
+		user.Role = domain.RoleSpectator
+	}
+	keys := joinLockoutKeys(client)
+	if err := h.joinLockout.check(keys, time.Now()); err != nil {
+		return err
+	}
+	if err := h.leaveCurrentRoom(ctx, client, payload.RoomID); err != nil {
+		return err
+	}
+	if payload.InviteCode != "" {
+		room, err = h.joinByInvite(ctx, user, payload.RoomID, payload.InviteCode)
+	} else {
+		room, err = h.JoinRoom(ctx, user, room, payload.Password)
+	}
+	if errors.Is(err, ErrWrongPassword) || errors.Is(err, ErrInviteNotFound) {
+		h.joinLockout.fail(keys, time.Now())
+	}
+	if err != nil {
+		return err
+	}
+	h.joinLockout.succeed(keys)
+	if err := h.Store.SaveUser(ctx, user); err != nil {
+		return err
+	}
+	h.acknowledgeJoin(ctx, client, user, room)
 	return nil
 }
+
+// newUser builds the caller's user. Handlers look users up by connection ID,
+// so the user takes the ID the client connected with.
+func (h *RoomEventHandler) newUser(client ClientConn, username, roomID string, isHost bool) (*domain.User, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, ErrMissingName
+	}
+	id, err := uuid.Parse(client.ID())
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	user := h.UserService.NewUser(username, roomID, isHost)
+	user.ID = id
+	user.ReconnectToken = GenerateReconnectToken()
+	return user, nil
+}
+
+// leaveCurrentRoom removes the caller from the room they are in, if it is not
+// the one they are joining.
+func (h *RoomEventHandler) leaveCurrentRoom(ctx context.Context, client ClientConn, roomID string) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	case user.RoomID == roomID:
+		return nil
+	}
+	return h.LeaveRoom(ctx, user)
+}
+
+// roomSettings reads the free-form settings map into RoomSettings; the
+// top-level password and private flag take precedence. The password is
+// kept only as a hash.
+func roomSettings(payload CreateRoomPayload) (domain.RoomSettings, error) {
+	var settings domain.RoomSettings
+	if len(payload.Settings) > 0 {
+		raw, err := json.Marshal(payload.Settings)
+		if err != nil {
+			return settings, ErrInvalidSetting
+		}
+		if err := json.Unmarshal(raw, &settings); err != nil {
+			return settings, ErrInvalidSetting
+		}
+	}
+	password := payload.Password
+	if password == "" {
+		password, _ = payload.Settings["password"].(string)
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return settings, err
+	}
+	settings.PasswordHash = hash
+	if payload.Private {
+		settings.Private = true
+	}
+	return settings, nil
+}
+
+// acknowledgeJoin tells the room about the new member, then subscribes the
+// caller and sends them the room in the joinedRoom ack. The broadcast goes
+// first so the caller does not also get it as a roomStateUpdate; the ack's
+// seq covers it.
+func (h *RoomEventHandler) acknowledgeJoin(ctx context.Context, client ClientConn, user *domain.User, room *domain.Room) {
+	h.sessions.attach(user.ID.String(), client)
+	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+	h.subscribe(room.ID, client)
+	ack := JoinedRoomPayload{
+		UserID:         user.ID.String(),
+		ReconnectToken: user.ReconnectToken,
+		Room:           room,
+	}
+	if h.Events != nil {
+		ack.Seq = h.Events.Seq(room.ID)
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventJoinedRoom,
+		"payload": ack,
+	}))
+	h.roomChanged(ctx, room.ID)
+}
@@ -0,0 +1,85 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/internal/storage"
+)
+
+func TestAdmitRechecksStatusForManagers(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	room := &domain.Room{ID: "r1", Status: domain.RoomWaiting, Users: map[string]*domain.User{}}
+	if err := store.SaveRoom(ctx, room); err != nil {
+		t.Fatal(err)
+	}
+	h := &RoomEventHandler{Store: store}
+	// The caller read the room while it was waiting; it has started since.
+	room.Status = domain.RoomAuction
+
+	manager := &domain.User{ID: uuid.New(), Username: "late", RoomID: "r1", Role: domain.RoleManager}
+	if _, err := h.admit(ctx, manager, "r1"); !errors.Is(err, ErrRoomStarted) {
+		t.Errorf("admitting a manager: err = %v, want ErrRoomStarted", err)
+	}
+	spectator := &domain.User{ID: uuid.New(), Username: "watcher", RoomID: "r1", Role: domain.RoleSpectator}
+	if _, err := h.admit(ctx, spectator, "r1"); err != nil {
+		t.Errorf("admitting a spectator: %v", err)
+	}
+	stored, _ := store.GetRoom(ctx, "r1")
+	if _, ok := stored.Users[manager.ID.String()]; ok {
+		t.Error("manager was seated in a started room")
+	}
+	if _, ok := stored.Users[spectator.ID.String()]; !ok {
+		t.Error("spectator was not seated")
+	}
+}
+
+type recordingClient struct {
+	id   string
+	sent []string
+}
+
+func (c *recordingClient) Send(msg []byte) {
+	var event struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(msg, &event)
+	c.sent = append(c.sent, event.Type)
+}
+
+func (c *recordingClient) ID() string { return c.id }
+
+func TestAcknowledgeJoinDoesNotEchoRoomState(t *testing.T) {
+	host := &recordingClient{id: "host"}
+	subscribed := map[ClientConn]bool{host: true}
+	h := &RoomEventHandler{
+		Store: storage.NewMemoryStore(),
+		Broadcast: func(roomID string, eventType EventType, data interface{}) {
+			for c := range subscribed {
+				c.Send(mustMarshal(map[string]interface{}{"type": eventType}))
+			}
+		},
+		Subscribe: func(roomID string, client ClientConn) { subscribed[client] = true },
+	}
+	user := manager()
+	joiner := &recordingClient{id: user.ID.String()}
+	room := &domain.Room{ID: "r1", Status: domain.RoomWaiting, Users: map[string]*domain.User{user.ID.String(): user}}
+
+	h.acknowledgeJoin(context.Background(), joiner, user, room)
+
+	if want := []string{string(EventJoinedRoom)}; !reflect.DeepEqual(joiner.sent, want) {
+		t.Errorf("joiner got %v, want %v", joiner.sent, want)
+	}
+	if want := []string{string(EventRoomStateUpdate)}; !reflect.DeepEqual(host.sent, want) {
+		t.Errorf("host got %v, want %v", host.sent, want)
+	}
+	if !subscribed[joiner] {
+		t.Error("joiner was not subscribed")
+	}
+}
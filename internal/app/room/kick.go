@@ -1,24 +1,30 @@
 package room
 
 import (
+	"context"
+
 	"github.com/yourusername/TouchlineTactics/internal/domain"
 )
 
 type KickUserPayload struct {
 	UserID string `json:"userId"`
+	Reason string `json:"reason,omitempty"`
 }
 
-func (h *RoomEventHandler) KickUser(host *domain.User, payload KickUserPayload) {
-	room, ok := h.Store.GetRoom(host.RoomID)
-	if !ok {
-		return
-	}
-	if room.HostID != host.ID.String() {
-		return // Only host can kick
+// KickUser removes the target from the room; unlike a ban they may rejoin.
+func (h *RoomEventHandler) KickUser(ctx context.Context, actor *domain.User, payload KickUserPayload) error {
+	err := h.moderate(ctx, actor, func(room *domain.Room) (domain.ModerationEntry, error) {
+		if err := canModerate(room, actor.ID.String(), payload.UserID); err != nil {
+			return domain.ModerationEntry{}, err
+		}
+		if _, ok := room.Users[payload.UserID]; !ok {
+			return domain.ModerationEntry{}, ErrUserNotFound
+		}
+		delete(room.Users, payload.UserID)
+		return domain.ModerationEntry{Action: domain.ModKick, TargetID: payload.UserID, Reason: payload.Reason}, nil
+	})
+	if err != nil {
+		return err
 	}
-	room.Mutex.Lock()
-	defer room.Mutex.Unlock()
-	delete(room.Users, payload.UserID)
-	h.Store.SaveRoom(room)
-	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+	return h.evict(ctx, actor.RoomID, payload.UserID)
 }
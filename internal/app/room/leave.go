@@ -1,29 +1,54 @@
 package room
 
 import (
+	"context"
+	"errors"
+
 	"github.com/yourusername/TouchlineTactics/internal/domain"
 )
 
-func (h *RoomEventHandler) LeaveRoom(user *domain.User) {
-	room, ok := h.Store.GetRoom(user.RoomID)
-	if !ok {
-		return
-	}
-	room.Mutex.Lock()
-	defer room.Mutex.Unlock()
-	delete(room.Users, user.ID.String())
-	if len(room.Users) == 0 {
-		h.Store.DeleteRoom(room.ID)
-		return
-	}
-	if room.HostID == user.ID.String() {
-		// Transfer host to another user
-		for _, u := range room.Users {
-			u.IsHost = true
-			room.HostID = u.ID.String()
-			break
+// errRoomEmpty stops LeaveRoom's update when nobody who keeps the room open
+// is left; the room is deleted instead.
+var errRoomEmpty = errors.New("room is empty")
+
+// LeaveRoom takes the user's seat away. Leaving a room that no longer exists
+// is not an error.
+func (h *RoomEventHandler) LeaveRoom(ctx context.Context, user *domain.User) error {
+	room, err := h.updateRoom(ctx, user.RoomID, func(room *domain.Room) error {
+		delete(room.Users, user.ID.String())
+		if len(managerIDs(room)) == 0 {
+			// Spectators alone do not keep a room open.
+			return errRoomEmpty
+		}
+		if room.HostID == user.ID.String() {
+			// Transfer host to a co-host if there is one, else another manager
+			managers := managerIDs(room)
+			next := room.Users[managers[0]]
+			for _, id := range managers {
+				if room.Users[id].IsCoHost {
+					next = room.Users[id]
+					break
+				}
+			}
+			next.IsHost, next.IsCoHost = true, false
+			room.HostID = next.ID.String()
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, ErrRoomNotFound):
+		return nil
+	case errors.Is(err, errRoomEmpty):
+		err = h.Store.DeleteRoom(ctx, user.RoomID)
+		if err == nil && h.Events != nil {
+			h.Events.Forget(user.RoomID)
 		}
+		h.roomChanged(ctx, user.RoomID)
+		return err
+	case err != nil:
+		return err
 	}
-	h.Store.SaveRoom(room)
 	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+	h.roomChanged(ctx, room.ID)
+	return nil
 }
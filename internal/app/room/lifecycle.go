@@ -0,0 +1,294 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/TouchlineTactics/internal/app/auction"
+	"github.com/yourusername/TouchlineTactics/internal/app/match"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
+)
+
+const DefaultMinUsers = 2
+
+// roomTransitions lists the statuses each status may move to. A PAUSED room
+// may also resume to the status it was paused from.
+var roomTransitions = map[domain.RoomStatus][]domain.RoomStatus{
+	domain.RoomWaiting:       {domain.RoomAuction, domain.RoomCancelled},
+	domain.RoomAuction:       {domain.RoomSquadBuilding, domain.RoomPaused, domain.RoomCancelled},
+	domain.RoomSquadBuilding: {domain.RoomCompetition, domain.RoomFinished, domain.RoomPaused, domain.RoomCancelled},
+	domain.RoomCompetition:   {domain.RoomFinished, domain.RoomCancelled},
+	domain.RoomPaused:        {domain.RoomCancelled},
+	domain.RoomFinished:      {},
+	domain.RoomCancelled:     {},
+}
+
+// squadLineTemplate is how many players of each line the default auction
+// offers per manager: enough for a 4-4-2.
+var squadLineTemplate = map[domain.PositionLine]int{
+	domain.LineGoalkeeper: 1,
+	domain.LineDefence:    4,
+	domain.LineMidfield:   4,
+	domain.LineForward:    2,
+}
+
+// TransitionOptions carries the settings for phases that start a service.
+// Nil fields fall back to the room settings and defaults.
+type TransitionOptions struct {
+	Auction     *auction.StartAuctionPayload
+	Competition *StartCompetitionPayload
+	// Force starts the auction without waiting for every manager to be ready.
+	Force bool
+}
+
+type TransitionError struct {
+	From   domain.RoomStatus `json:"from"`
+	To     domain.RoomStatus `json:"to"`
+	Reason string            `json:"reason"`
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot move room from %s to %s: %s", e.From, e.To, e.Reason)
+}
+
+// CanTransition reports whether the transition table allows from -> to.
+// pausedFrom is the status a PAUSED room would resume to.
+func CanTransition(from, to, pausedFrom domain.RoomStatus) bool {
+	if from == domain.RoomPaused && to == pausedFrom && to != "" {
+		return true
+	}
+	for _, s := range roomTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves a room to a new status. It checks the transition table
+// and the guard for the target phase, applies the status, then runs the
+// hooks that start, pause, resume or stop the auction and competition
+// services. If a hook fails the previous status is restored.
+func (h *RoomEventHandler) Transition(ctx context.Context, roomID string, to domain.RoomStatus, opts TransitionOptions) error {
+	var from, pausedFrom domain.RoomStatus
+	room, err := h.updateRoom(ctx, roomID, func(room *domain.Room) error {
+		from, pausedFrom = room.Status, room.PausedFrom
+		if !CanTransition(from, to, pausedFrom) {
+			return &TransitionError{From: from, To: to, Reason: "transition not allowed"}
+		}
+		reason, err := h.guard(ctx, room, from, to, opts.Force)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			return &TransitionError{From: from, To: to, Reason: reason}
+		}
+		room.Status = to
+		switch {
+		case to == domain.RoomPaused:
+			room.PausedFrom = from
+		case from == domain.RoomPaused:
+			room.PausedFrom = ""
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := h.runTransitionHooks(ctx, room, from, to, pausedFrom, opts); err != nil {
+		h.restoreStatus(ctx, room.ID, from, pausedFrom)
+		return &TransitionError{From: from, To: to, Reason: err.Error()}
+	}
+	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+	h.roomChanged(ctx, room.ID)
+	return nil
+}
+
+// restoreStatus undoes a failed transition.
+func (h *RoomEventHandler) restoreStatus(ctx context.Context, roomID string, status, pausedFrom domain.RoomStatus) {
+	_, err := h.updateRoom(ctx, roomID, func(room *domain.Room) error {
+		room.Status, room.PausedFrom = status, pausedFrom
+		return nil
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("restoring room %s to %s: %v", roomID, status, err))
+	}
+}
+
+// guard returns why the room cannot enter the target phase, or "". With
+// force the auction may start before everyone is ready.
+// Callers must hold room.Mutex.
+func (h *RoomEventHandler) guard(ctx context.Context, room *domain.Room, from, to domain.RoomStatus, force bool) (string, error) {
+	switch {
+	case from == domain.RoomWaiting && to == domain.RoomAuction:
+		min := room.Settings.MinUsers
+		if min == 0 {
+			min = DefaultMinUsers
+		}
+		managers := managerIDs(room)
+		if len(managers) < min {
+			return fmt.Sprintf("at least %d players are needed", min), nil
+		}
+		if force {
+			break
+		}
+		for _, id := range managers {
+			if u := room.Users[id]; !u.Ready {
+				return u.Username + " is not ready", nil
+			}
+		}
+	case to == domain.RoomCompetition:
+		withSquads := 0
+		for _, id := range managerIDs(room) {
+			team, err := h.Store.GetTeam(ctx, room.ID, id)
+			if err != nil {
+				return "", err
+			}
+			if len(team) > 0 {
+				withSquads++
+			}
+		}
+		if withSquads < 2 {
+			return "at least two managers need a squad", nil
+		}
+	}
+	return "", nil
+}
+
+func (h *RoomEventHandler) runTransitionHooks(ctx context.Context, room *domain.Room, from, to, pausedFrom domain.RoomStatus, opts TransitionOptions) error {
+	// The phase being left, looking through a pause.
+	left := from
+	if from == domain.RoomPaused {
+		left = pausedFrom
+	}
+	switch {
+	case from == domain.RoomWaiting && to == domain.RoomAuction:
+		return h.startAuction(ctx, room, opts.Auction)
+	case to == domain.RoomCompetition:
+		return h.startCompetition(room, opts.Competition)
+	case from == domain.RoomAuction && to == domain.RoomPaused:
+		if h.AuctionHandler != nil {
+			h.AuctionHandler.Auction.Pause(room.ID)
+		}
+	case from == domain.RoomPaused && to == domain.RoomAuction:
+		if h.AuctionHandler != nil {
+			h.AuctionHandler.Auction.Resume(room.ID)
+		}
+	case left == domain.RoomAuction:
+		if h.AuctionHandler != nil {
+			h.AuctionHandler.Auction.Stop(room.ID)
+		}
+	case left == domain.RoomCompetition:
+		if h.Competitions != nil {
+			h.Competitions.Cancel(room.ID)
+		}
+	}
+	return nil
+}
+
+// startAuction fills in the pool profile, seed, squad quota and budget from
+// the room settings unless the request overrides them, draws from the room's
+// custom player pool when one was uploaded, and by default offers a 4-4-2's
+// worth of players per manager.
+func (h *RoomEventHandler) startAuction(ctx context.Context, room *domain.Room, payload *auction.StartAuctionPayload) error {
+	if h.AuctionHandler == nil {
+		return ErrUnavailable
+	}
+	if payload == nil {
+		payload = &auction.StartAuctionPayload{}
+	}
+	room.Mutex.RLock()
+	payload.RoomID = room.ID
+	if payload.NumPlayers == 0 && len(payload.Positions) == 0 {
+		payload.Positions = make(map[string]int, len(squadLineTemplate))
+		for line, n := range squadLineTemplate {
+			payload.Positions[string(line)] = n * len(managerIDs(room))
+		}
+	}
+	if payload.Profile == "" {
+		payload.Profile = room.Settings.PoolProfile
+	}
+	if payload.Seed == 0 {
+		payload.Seed = room.Settings.PoolSeed
+	}
+	if payload.Quota == nil {
+		payload.Quota = room.Settings.SquadQuota
+	}
+	if payload.Budget == 0 {
+		payload.Budget = room.Settings.Budget
+	}
+	room.Mutex.RUnlock()
+	pool, err := h.Store.GetPlayerPool(ctx, room.ID)
+	switch {
+	case err == nil:
+		payload.Pool = pool
+	case !errors.Is(err, domain.ErrNotFound):
+		return err
+	}
+	return h.AuctionHandler.HandleStartAuction(*payload)
+}
+
+// startCompetition runs a league or knockout among the room's managers and
+// finishes the room once a champion is known.
+func (h *RoomEventHandler) startCompetition(room *domain.Room, payload *StartCompetitionPayload) error {
+	if h.Competitions == nil {
+		return ErrUnavailable
+	}
+	if payload == nil {
+		payload = &StartCompetitionPayload{Format: domain.CompetitionLeague}
+	}
+	room.Mutex.RLock()
+	managers := managerIDs(room)
+	room.Mutex.RUnlock()
+
+	roomID := room.ID
+	teams := func(userID string) match.Team {
+		team, err := h.matchTeam(context.Background(), roomID, userID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("loading the team of %s in room %s: %v", userID, roomID, err))
+			team.UserID = userID
+		}
+		return team
+	}
+	onFinish := func(domain.Competition) {
+		if err := h.Transition(context.Background(), roomID, domain.RoomFinished, TransitionOptions{}); err != nil {
+			logger.Error(fmt.Sprintf("finishing room %s: %v", roomID, err))
+		}
+	}
+	_, err := h.Competitions.Start(roomID, payload.Format, managers, payload.Legs, payload.Seed, teams, onFinish)
+	return err
+}
+
+// HandleAuctionComplete moves the room on to squad building once every player
+// has been auctioned.
+func (h *RoomEventHandler) HandleAuctionComplete(roomID string) {
+	ctx := context.Background()
+	if err := h.PostSystemMessage(ctx, roomID, "The auction is over"); err != nil {
+		logger.Error(fmt.Sprintf("posting to room %s: %v", roomID, err))
+	}
+	if err := h.Transition(ctx, roomID, domain.RoomSquadBuilding, TransitionOptions{}); err != nil {
+		logger.Error(fmt.Sprintf("moving room %s to squad building: %v", roomID, err))
+	}
+}
+
+// requestTransition is the host-initiated path into Transition.
+func (h *RoomEventHandler) requestTransition(ctx context.Context, client ClientConn, to domain.RoomStatus, opts TransitionOptions) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	room, err := h.Store.GetRoom(ctx, user.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	if room.HostID != user.ID.String() {
+		return ErrNotHost
+	}
+	if _, known := roomTransitions[to]; !known {
+		return ErrUnknownPhase
+	}
+	return h.Transition(ctx, room.ID, to, opts)
+}
@@ -0,0 +1,84 @@
+package room
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/internal/storage"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to, pausedFrom domain.RoomStatus
+		want                 bool
+	}{
+		{domain.RoomWaiting, domain.RoomAuction, "", true},
+		{domain.RoomWaiting, domain.RoomCancelled, "", true},
+		{domain.RoomWaiting, domain.RoomSquadBuilding, "", false},
+		{domain.RoomWaiting, domain.RoomPaused, "", false},
+		{domain.RoomAuction, domain.RoomSquadBuilding, "", true},
+		{domain.RoomAuction, domain.RoomPaused, "", true},
+		{domain.RoomAuction, domain.RoomWaiting, "", false},
+		{domain.RoomSquadBuilding, domain.RoomCompetition, "", true},
+		{domain.RoomSquadBuilding, domain.RoomFinished, "", true},
+		{domain.RoomSquadBuilding, domain.RoomPaused, "", true},
+		{domain.RoomSquadBuilding, domain.RoomAuction, "", false},
+		{domain.RoomCompetition, domain.RoomFinished, "", true},
+		{domain.RoomCompetition, domain.RoomCancelled, "", true},
+		{domain.RoomCompetition, domain.RoomPaused, "", false},
+		// A paused room resumes only to the status it was paused from.
+		{domain.RoomPaused, domain.RoomAuction, domain.RoomAuction, true},
+		{domain.RoomPaused, domain.RoomSquadBuilding, domain.RoomSquadBuilding, true},
+		{domain.RoomPaused, domain.RoomSquadBuilding, domain.RoomAuction, false},
+		{domain.RoomPaused, domain.RoomAuction, "", false},
+		{domain.RoomPaused, "", "", false},
+		{domain.RoomPaused, domain.RoomCancelled, domain.RoomAuction, true},
+		{domain.RoomFinished, domain.RoomWaiting, "", false},
+		{domain.RoomCancelled, domain.RoomWaiting, "", false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to, tt.pausedFrom); got != tt.want {
+			t.Errorf("CanTransition(%s, %s, %q) = %v, want %v", tt.from, tt.to, tt.pausedFrom, got, tt.want)
+		}
+	}
+}
+
+func TestGuardStartingTheAuction(t *testing.T) {
+	user := func(name string, role domain.UserRole, ready bool) *domain.User {
+		return &domain.User{ID: uuid.New(), Username: name, Role: role, Ready: ready}
+	}
+	tests := []struct {
+		name  string
+		users []*domain.User
+		min   int
+		force bool
+		want  string
+	}{
+		{"everyone ready", []*domain.User{user("a", domain.RoleManager, true), user("b", domain.RoleManager, true)}, 0, false, ""},
+		{"too few managers", []*domain.User{user("a", domain.RoleManager, true), user("s", domain.RoleSpectator, true)}, 0, false, "at least 2 players are needed"},
+		{"room minimum", []*domain.User{user("a", domain.RoleManager, true), user("b", domain.RoleManager, true)}, 3, false, "at least 3 players are needed"},
+		{"not ready", []*domain.User{user("a", domain.RoleManager, true), user("b", domain.RoleManager, false)}, 0, false, "b is not ready"},
+		{"forced", []*domain.User{user("a", domain.RoleManager, true), user("b", domain.RoleManager, false)}, 0, true, ""},
+		{"forced with too few", []*domain.User{user("a", domain.RoleManager, false)}, 0, true, "at least 2 players are needed"},
+		{"spectators need not be ready", []*domain.User{user("a", domain.RoleManager, true), user("b", domain.RoleManager, true), user("s", domain.RoleSpectator, false)}, 0, false, ""},
+	}
+	h := &RoomEventHandler{Store: storage.NewMemoryStore()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &domain.Room{ID: "r1", Status: domain.RoomWaiting, Users: map[string]*domain.User{}}
+			room.Settings.MinUsers = tt.min
+			for _, u := range tt.users {
+				room.Users[u.ID.String()] = u
+			}
+			got, err := h.guard(context.Background(), room, domain.RoomWaiting, domain.RoomAuction, tt.force)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("guard() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package room
+
+import (
+	"context"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+type SetLineupPayload struct {
+	Formation string            `json:"formation"`
+	Slots     map[string]string `json:"slots"`
+	Bench     []string          `json:"bench"`
+	Captain   string            `json:"captain,omitempty"`
+	// Auto ignores Slots, Bench and Captain and picks the best lineup for
+	// the formation from the manager's squad.
+	Auto bool `json:"auto,omitempty"`
+}
+
+type GetLineupPayload struct {
+	UserID string `json:"userId,omitempty"` // defaults to the caller
+}
+
+type TeamRatingPayload struct {
+	UserID string `json:"userId,omitempty"` // defaults to the caller
+}
+
+func (h *RoomEventHandler) HandleSetLineup(ctx context.Context, client ClientConn, payload SetLineupPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	squad, err := h.Store.GetTeam(ctx, user.RoomID, user.ID.String())
+	if err != nil {
+		return err
+	}
+	var lineup *domain.Lineup
+	if payload.Auto {
+		lineup = h.Lineups.Auto(user.RoomID, user.ID.String(), payload.Formation, squad)
+	} else {
+		lineup = &domain.Lineup{
+			RoomID:    user.RoomID,
+			UserID:    user.ID.String(),
+			Formation: payload.Formation,
+			Slots:     payload.Slots,
+			Bench:     payload.Bench,
+			Captain:   payload.Captain,
+		}
+		if err := h.Lineups.Validate(lineup, squad); err != nil {
+			return err
+		}
+	}
+	if err := h.Store.SaveLineup(ctx, lineup); err != nil {
+		return err
+	}
+	h.Broadcast(user.RoomID, EventLineupUpdate, lineup)
+	return nil
+}
+
+func (h *RoomEventHandler) HandleGetLineup(ctx context.Context, client ClientConn, payload GetLineupPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	userID := payload.UserID
+	if userID == "" {
+		userID = user.ID.String()
+	}
+	squad, lineup, err := h.lineup(ctx, user.RoomID, userID)
+	if err != nil {
+		return err
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type": EventGetLineup,
+		"payload": map[string]interface{}{
+			"lineup":     lineup,
+			"squad":      squad,
+			"formations": domain.Formations,
+		},
+	}))
+	return nil
+}
+
+// HandleTeamRating rates a manager's saved lineup, or the best automatic
+// lineup for their squad if they have not picked one yet.
+func (h *RoomEventHandler) HandleTeamRating(ctx context.Context, client ClientConn, payload TeamRatingPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	userID := payload.UserID
+	if userID == "" {
+		userID = user.ID.String()
+	}
+	squad, lineup, err := h.lineup(ctx, user.RoomID, userID)
+	if err != nil {
+		return err
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type": EventTeamRating,
+		"payload": map[string]interface{}{
+			"userId": userID,
+			"lineup": lineup,
+			"rating": h.Lineups.Rate(lineup, squad),
+		},
+	}))
+	return nil
+}
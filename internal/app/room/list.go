@@ -1,29 +1,195 @@
 package room
 
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+const (
+	DefaultRoomPageSize = 20
+	MaxRoomPageSize     = 100
+)
+
+var ErrInvalidRoomQuery = errors.New("invalid room query")
+
 type RoomListItem struct {
 	ID       string `json:"id"`
-	Host     string `json:"host"`
+	Host     string `json:"host"` // the host's username; IDs stay private
 	Status   string `json:"status"`
+	GameMode string `json:"gameMode,omitempty"`
 	NumUsers int    `json:"numUsers"`
 	MaxUsers int    `json:"maxUsers"`
-	Private  bool   `json:"private"`
+	// FreeSeats is how many manager seats are left.
+	FreeSeats int  `json:"freeSeats"`
+	Private   bool `json:"private"`
+	// NumSpectators is how many users are watching rather than managing.
+	NumSpectators int       `json:"numSpectators"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// RoomQuery filters and pages the public room list. Zero values mean "no
+// constraint".
+type RoomQuery struct {
+	GameMode  string `json:"gameMode,omitempty" query:"gameMode"`
+	Status    string `json:"status,omitempty" query:"status"`
+	FreeSeats int    `json:"freeSeats,omitempty" query:"freeSeats"` // at least this many
+	SortBy    string `json:"sortBy,omitempty" query:"sortBy"`       // newest, players or freeSeats
+	Order     string `json:"order,omitempty" query:"order"`         // asc or desc
+	Limit     int    `json:"limit,omitempty" query:"limit"`
+	Offset    int    `json:"offset,omitempty" query:"offset"`
+}
+
+type RoomPage struct {
+	Rooms  []RoomListItem `json:"rooms"`
+	Total  int            `json:"total"`
+	Offset int            `json:"offset"`
+	Limit  int            `json:"limit"`
+}
+
+// roomSortKeys compares two items by each public sort key, ascending.
+var roomSortKeys = map[string]func(a, b RoomListItem) int{
+	"newest":    func(a, b RoomListItem) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"players":   func(a, b RoomListItem) int { return a.NumUsers - b.NumUsers },
+	"freeSeats": func(a, b RoomListItem) int { return a.FreeSeats - b.FreeSeats },
+}
+
+// roomListItem lists the room. Callers must hold at least a read lock on
+// room.Mutex if the room is shared.
+func roomListItem(room *domain.Room) RoomListItem {
+	var host string
+	if u, ok := room.Users[room.HostID]; ok {
+		host = u.Username
+	}
+	managers := len(managerIDs(room))
+	free := maxManagers(room) - managers
+	if free < 0 {
+		free = 0
+	}
+	return RoomListItem{
+		ID:            room.ID,
+		Host:          host,
+		Status:        string(room.Status),
+		GameMode:      room.Settings.GameMode,
+		NumUsers:      managers,
+		MaxUsers:      maxManagers(room),
+		FreeSeats:     free,
+		Private:       room.Settings.Private,
+		NumSpectators: len(room.Users) - managers,
+		CreatedAt:     room.CreatedAt,
+	}
 }
 
-func (h *RoomEventHandler) ListRooms() []RoomListItem {
+// Matches reports whether the item passes the query's filters.
+func (q RoomQuery) Matches(item RoomListItem) bool {
+	if q.GameMode != "" && !strings.EqualFold(q.GameMode, item.GameMode) {
+		return false
+	}
+	if q.Status != "" && !strings.EqualFold(q.Status, item.Status) {
+		return false
+	}
+	return item.FreeSeats >= q.FreeSeats
+}
+
+func (q RoomQuery) validate() error {
+	if q.SortBy != "" {
+		if _, ok := roomSortKeys[q.SortBy]; !ok {
+			return ErrInvalidRoomQuery
+		}
+	}
+	if q.Order != "" && q.Order != "asc" && q.Order != "desc" {
+		return ErrInvalidRoomQuery
+	}
+	if q.FreeSeats < 0 || q.Limit < 0 || q.Offset < 0 {
+		return ErrInvalidRoomQuery
+	}
+	return nil
+}
+
+// ListRooms returns every public room.
+func (h *RoomEventHandler) ListRooms(ctx context.Context) ([]RoomListItem, error) {
+	all, err := h.Store.ListRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var rooms []RoomListItem
-	for _, room := range h.Store.ListRooms() {
+	for _, room := range all {
 		room.Mutex.RLock()
-		if !room.Settings.Private && !IsRoomAtCapacity(room) {
-			rooms = append(rooms, RoomListItem{
-				ID:       room.ID,
-				Host:     room.HostID,
-				Status:   string(room.Status),
-				NumUsers: len(room.Users),
-				MaxUsers: room.Settings.MaxUsers,
-				Private:  room.Settings.Private,
-			})
+		if !room.Settings.Private {
+			rooms = append(rooms, roomListItem(room))
 		}
 		room.Mutex.RUnlock()
 	}
-	return rooms
+	return rooms, nil
+}
+
+// SearchRooms returns one page of public rooms matching the query, newest
+// first unless another order is asked for. Ties are broken by room ID so
+// pages are stable.
+func (h *RoomEventHandler) SearchRooms(ctx context.Context, query RoomQuery) (RoomPage, error) {
+	if err := query.validate(); err != nil {
+		return RoomPage{}, err
+	}
+	listed, err := h.ListRooms(ctx)
+	if err != nil {
+		return RoomPage{}, err
+	}
+	rooms := make([]RoomListItem, 0)
+	for _, item := range listed {
+		if query.Matches(item) {
+			rooms = append(rooms, item)
+		}
+	}
+	cmp := roomSortKeys["newest"]
+	if query.SortBy != "" {
+		cmp = roomSortKeys[query.SortBy]
+	}
+	desc := query.Order != "asc"
+	sort.Slice(rooms, func(i, j int) bool {
+		c := cmp(rooms[i], rooms[j])
+		if c == 0 {
+			return rooms[i].ID < rooms[j].ID
+		}
+		return (c < 0) != desc
+	})
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = DefaultRoomPageSize
+	}
+	if limit > MaxRoomPageSize {
+		limit = MaxRoomPageSize
+	}
+	page := RoomPage{Total: len(rooms), Offset: query.Offset, Limit: limit}
+	start := min(query.Offset, len(rooms))
+	end := min(start+limit, len(rooms))
+	page.Rooms = rooms[start:end]
+	return page, nil
+}
+
+// HandleListRooms sends a page of the lobby. With Subscribe set the caller
+// also receives lobbyUpdate messages for rooms matching the same filters
+// until it sends unsubscribeLobby or disconnects.
+func (h *RoomEventHandler) HandleListRooms(ctx context.Context, client ClientConn, payload ListRoomsPayload) error {
+	page, err := h.SearchRooms(ctx, payload.RoomQuery)
+	if err != nil {
+		return err
+	}
+	if payload.Subscribe {
+		h.lobby.subscribe(client, payload.RoomQuery)
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventListRooms,
+		"payload": page,
+	}))
+	return nil
+}
+
+func (h *RoomEventHandler) HandleUnsubscribeLobby(ctx context.Context, client ClientConn, payload UnsubscribeLobbyPayload) error {
+	h.lobby.unsubscribe(client)
+	return nil
 }
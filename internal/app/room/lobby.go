@@ -0,0 +1,130 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
+)
+
+// Lobby update actions, relative to each subscriber's filters.
+const (
+	LobbyRoomAdded   = "added"
+	LobbyRoomUpdated = "updated"
+	LobbyRoomRemoved = "removed"
+)
+
+// LobbyUpdate is the payload of a lobbyUpdate message. Room is the new
+// listing, or the last one seen when the room was removed.
+type LobbyUpdate struct {
+	Action string       `json:"action"`
+	Room   RoomListItem `json:"room"`
+}
+
+type lobbySubscriber struct {
+	client ClientConn
+	query  RoomQuery
+}
+
+// lobby pushes room listing changes to the clients browsing the lobby.
+type lobby struct {
+	subscribers map[string]lobbySubscriber // clientID -> subscriber
+	listed      map[string]RoomListItem    // roomID -> last published listing
+	mutex       sync.Mutex
+}
+
+func (l *lobby) subscribe(client ClientConn, query RoomQuery) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.subscribers == nil {
+		l.subscribers = make(map[string]lobbySubscriber)
+	}
+	l.subscribers[client.ID()] = lobbySubscriber{client: client, query: query}
+}
+
+// unsubscribe drops client, unless its user has since subscribed on
+// another connection.
+func (l *lobby) unsubscribe(client ClientConn) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if sub, ok := l.subscribers[client.ID()]; ok && sub.client == client {
+		delete(l.subscribers, client.ID())
+	}
+}
+
+// publish records the room's listing and tells each subscriber whose
+// filters it entered, changed within or left. A nil item means the room is
+// no longer listed.
+func (l *lobby) publish(roomID string, item *RoomListItem) {
+	l.mutex.Lock()
+	prev, wasListed := l.listed[roomID]
+	if item == nil {
+		delete(l.listed, roomID)
+	} else {
+		if l.listed == nil {
+			l.listed = make(map[string]RoomListItem)
+		}
+		l.listed[roomID] = *item
+	}
+	if !wasListed && item == nil || wasListed && item != nil && prev == *item {
+		l.mutex.Unlock()
+		return
+	}
+	type delivery struct {
+		client ClientConn
+		update LobbyUpdate
+	}
+	var deliveries []delivery
+	for _, sub := range l.subscribers {
+		before := wasListed && sub.query.Matches(prev)
+		after := item != nil && sub.query.Matches(*item)
+		switch {
+		case before && after:
+			deliveries = append(deliveries, delivery{sub.client, LobbyUpdate{LobbyRoomUpdated, *item}})
+		case after:
+			deliveries = append(deliveries, delivery{sub.client, LobbyUpdate{LobbyRoomAdded, *item}})
+		case before:
+			deliveries = append(deliveries, delivery{sub.client, LobbyUpdate{LobbyRoomRemoved, prev}})
+		}
+	}
+	l.mutex.Unlock()
+	for _, d := range deliveries {
+		d.client.Send(mustMarshal(map[string]interface{}{
+			"type":    EventLobbyUpdate,
+			"payload": d.update,
+		}))
+	}
+}
+
+// roomChanged follows up a change to a room's members, settings or status:
+// the lobby listing is refreshed and the auto-start countdown started or
+// cancelled. Callers must not hold room.Mutex.
+func (h *RoomEventHandler) roomChanged(ctx context.Context, roomID string) {
+	h.publishLobby(ctx, roomID)
+	h.checkAutoStart(ctx, roomID)
+}
+
+// publishLobby pushes the room's current listing to lobby subscribers.
+// Callers must not hold room.Mutex.
+func (h *RoomEventHandler) publishLobby(ctx context.Context, roomID string) {
+	room, err := h.Store.GetRoom(ctx, roomID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		h.lobby.publish(roomID, nil)
+		return
+	case err != nil:
+		logger.Error(fmt.Sprintf("publishing room %s to the lobby: %v", roomID, err))
+		return
+	}
+	room.Mutex.RLock()
+	var item *RoomListItem
+	if !room.Settings.Private {
+		listing := roomListItem(room)
+		item = &listing
+	}
+	room.Mutex.RUnlock()
+	h.lobby.publish(roomID, item)
+}
@@ -0,0 +1,89 @@
+package room
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxJoinFailures wrong passwords or invite codes within
+	// JoinFailureWindow lock the caller out of joining for JoinLockout.
+	MaxJoinFailures   = 5
+	JoinFailureWindow = 10 * time.Minute
+	JoinLockout       = 15 * time.Minute
+)
+
+var ErrTooManyAttempts = errors.New("too many failed attempts, try again later")
+
+// AddrConn is implemented by connections that know the client's address,
+// so failed joins can be counted per IP as well as per user.
+type AddrConn interface {
+	RemoteAddr() string
+}
+
+type joinAttempts struct {
+	failures    int
+	first       time.Time
+	lockedUntil time.Time
+}
+
+// joinLockout counts failed joins per key ("user:<id>", "ip:<addr>").
+type joinLockout struct {
+	attempts map[string]*joinAttempts
+	mutex    sync.Mutex
+}
+
+func joinLockoutKeys(client ClientConn) []string {
+	keys := []string{"user:" + client.ID()}
+	if c, ok := client.(AddrConn); ok && c.RemoteAddr() != "" {
+		keys = append(keys, "ip:"+c.RemoteAddr())
+	}
+	return keys
+}
+
+// check returns ErrTooManyAttempts while any of the keys is locked out.
+func (l *joinLockout) check(keys []string, now time.Time) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, key := range keys {
+		if a, ok := l.attempts[key]; ok && now.Before(a.lockedUntil) {
+			return ErrTooManyAttempts
+		}
+	}
+	return nil
+}
+
+func (l *joinLockout) fail(keys []string, now time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.attempts == nil {
+		l.attempts = make(map[string]*joinAttempts)
+	}
+	for key, a := range l.attempts {
+		if now.Sub(a.first) > JoinFailureWindow && now.After(a.lockedUntil) {
+			delete(l.attempts, key)
+		}
+	}
+	for _, key := range keys {
+		a, ok := l.attempts[key]
+		if !ok {
+			a = &joinAttempts{first: now}
+			l.attempts[key] = a
+		}
+		a.failures++
+		if a.failures >= MaxJoinFailures {
+			a.lockedUntil = now.Add(JoinLockout)
+			a.failures = 0
+			a.first = now
+		}
+	}
+}
+
+// succeed clears the user's failures. The IP count is left to expire so
+// that joining an open room does not reset it.
+func (l *joinLockout) succeed(keys []string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.attempts, keys[0])
+}
@@ -0,0 +1,78 @@
+package room
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJoinLockout(t *testing.T) {
+	var l joinLockout
+	keys := []string{"user:u1", "ip:10.0.0.1"}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < MaxJoinFailures-1; i++ {
+		l.fail(keys, now)
+		if err := l.check(keys, now); err != nil {
+			t.Fatalf("locked out after %d failures", i+1)
+		}
+	}
+	l.fail(keys, now)
+	if err := l.check(keys, now); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("after %d failures: err = %v, want ErrTooManyAttempts", MaxJoinFailures, err)
+	}
+	// Either key locks the caller out, so a new user on the same address is
+	// refused too.
+	if err := l.check([]string{"user:u2", "ip:10.0.0.1"}, now); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("other user on the same address: err = %v, want ErrTooManyAttempts", err)
+	}
+	if err := l.check([]string{"user:u3", "ip:10.0.0.2"}, now); err != nil {
+		t.Errorf("unrelated caller: err = %v", err)
+	}
+
+	if err := l.check(keys, now.Add(JoinLockout-time.Second)); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("just before the lockout ends: err = %v, want ErrTooManyAttempts", err)
+	}
+	later := now.Add(JoinLockout)
+	if err := l.check(keys, later); err != nil {
+		t.Errorf("after the lockout: err = %v", err)
+	}
+	// The count starts again once the lockout is over.
+	l.fail(keys, later)
+	if err := l.check(keys, later); err != nil {
+		t.Errorf("one failure after the lockout: err = %v", err)
+	}
+}
+
+func TestJoinLockoutForgetsOldFailures(t *testing.T) {
+	var l joinLockout
+	keys := []string{"user:u1"}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < MaxJoinFailures-1; i++ {
+		l.fail(keys, now)
+	}
+	now = now.Add(JoinFailureWindow + time.Second)
+	l.fail(keys, now)
+	if err := l.check(keys, now); err != nil {
+		t.Errorf("failures outside the window counted: err = %v", err)
+	}
+}
+
+func TestJoinLockoutSucceedClearsTheUser(t *testing.T) {
+	var l joinLockout
+	keys := []string{"user:u1", "ip:10.0.0.1"}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < MaxJoinFailures-1; i++ {
+		l.fail(keys, now)
+	}
+	l.succeed(keys)
+	l.fail([]string{"user:u1"}, now)
+	if err := l.check([]string{"user:u1"}, now); err != nil {
+		t.Errorf("user count survived a successful join: err = %v", err)
+	}
+	// The address keeps its count.
+	l.fail([]string{"ip:10.0.0.1"}, now)
+	if err := l.check([]string{"ip:10.0.0.1"}, now); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("address count was reset by a successful join: err = %v", err)
+	}
+}
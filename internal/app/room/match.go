@@ -0,0 +1,77 @@
+package room
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yourusername/TouchlineTactics/internal/app/match"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+type StartMatchPayload struct {
+	HomeUserID string `json:"homeUserId"`
+	AwayUserID string `json:"awayUserId"`
+	Seed       int64  `json:"seed,omitempty"`
+}
+
+// HandleStartMatch lets the host play a friendly between two managers in the
+// room. The match is streamed to the room as matchEvent messages.
+func (h *RoomEventHandler) HandleStartMatch(ctx context.Context, client ClientConn, payload StartMatchPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	room, err := h.Store.GetRoom(ctx, user.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	if room.HostID != user.ID.String() {
+		return ErrNotHost
+	}
+	if h.Matches == nil {
+		return ErrUnavailable
+	}
+	if payload.HomeUserID == payload.AwayUserID {
+		return ErrSameManagers
+	}
+	if !isManager(room, payload.HomeUserID) || !isManager(room, payload.AwayUserID) {
+		return ErrUserNotFound
+	}
+	home, err := h.matchTeam(ctx, room.ID, payload.HomeUserID)
+	if err != nil {
+		return err
+	}
+	away, err := h.matchTeam(ctx, room.ID, payload.AwayUserID)
+	if err != nil {
+		return err
+	}
+	h.Matches.Play(room.ID, home, away, payload.Seed, nil)
+	return nil
+}
+
+// matchTeam loads a manager's saved lineup, falling back to the best
+// automatic lineup for their squad.
+func (h *RoomEventHandler) matchTeam(ctx context.Context, roomID, userID string) (match.Team, error) {
+	squad, lineup, err := h.lineup(ctx, roomID, userID)
+	if err != nil {
+		return match.Team{}, err
+	}
+	return match.Team{UserID: userID, Lineup: lineup, Squad: squad}, nil
+}
+
+// lineup loads a manager's squad and saved lineup, or the best automatic
+// lineup for the squad if they have not picked one.
+func (h *RoomEventHandler) lineup(ctx context.Context, roomID, userID string) ([]domain.Player, *domain.Lineup, error) {
+	squad, err := h.Store.GetTeam(ctx, roomID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	lineup, err := h.Store.GetLineup(ctx, roomID, userID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		lineup = h.Lineups.Auto(roomID, userID, domain.DefaultFormation, squad)
+	case err != nil:
+		return nil, nil, err
+	}
+	return squad, lineup, nil
+}
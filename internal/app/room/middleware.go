@@ -0,0 +1,202 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
+)
+
+const (
+	// DefaultRateLimit and DefaultRateBurst bound how many messages a
+	// connection may send: a sustained rate per second plus a burst.
+	DefaultRateLimit = 10
+	DefaultRateBurst = 20
+
+	rateLimitIdle = 10 * time.Minute
+)
+
+var ErrRateLimited = errors.New("too many messages, slow down")
+
+// Recover turns a panicking handler into an internal error frame.
+func Recover() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(cmd *Command) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error(fmt.Sprintf("panic handling %s from %s: %v\n%s", cmd.Type, cmd.Client.ID(), r, debug.Stack()))
+					err = &HandlerError{Code: CodeInternal, Err: errors.New("internal error")}
+				}
+			}()
+			return next(cmd)
+		}
+	}
+}
+
+// Logging logs how long each command took. Only internal failures are
+// logged as errors; rejected requests are the client's mistake.
+func Logging() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(cmd *Command) error {
+			start := time.Now()
+			err := next(cmd)
+			switch {
+			case err != nil && errorCode(err) == CodeInternal:
+				logger.Error(fmt.Sprintf("ws %s from %s failed after %s: %v", cmd.Type, cmd.Client.ID(), time.Since(start), err))
+			case err != nil:
+				logger.Info(fmt.Sprintf("ws %s from %s rejected after %s: %v", cmd.Type, cmd.Client.ID(), time.Since(start), err))
+			default:
+				logger.Info(fmt.Sprintf("ws %s from %s took %s", cmd.Type, cmd.Client.ID(), time.Since(start)))
+			}
+			return err
+		}
+	}
+}
+
+// Authenticated requires the connection to belong to a known user.
+func Authenticated(store Store) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(cmd *Command) error {
+			if cmd.User == nil {
+				user, err := store.GetUser(cmd.Ctx, cmd.Client.ID())
+				if err != nil {
+					return missing(err, ErrNotInRoom)
+				}
+				cmd.User = user
+			}
+			return next(cmd)
+		}
+	}
+}
+
+// RoomMember requires the caller to be seated in an existing room.
+func RoomMember(store Store) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return Authenticated(store)(func(cmd *Command) error {
+			if cmd.Room == nil {
+				room, err := store.GetRoom(cmd.Ctx, cmd.User.RoomID)
+				if err != nil {
+					return missing(err, ErrRoomNotFound)
+				}
+				room.Mutex.RLock()
+				_, seated := room.Users[cmd.User.ID.String()]
+				room.Mutex.RUnlock()
+				if !seated {
+					return ErrNotInRoom
+				}
+				cmd.Room = room
+			}
+			return next(cmd)
+		})
+	}
+}
+
+// ManagerOnly requires the caller to hold a manager seat, keeping
+// spectators read-only.
+func ManagerOnly(store Store) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return RoomMember(store)(func(cmd *Command) error {
+			if cmd.User.IsSpectator() {
+				return ErrSpectator
+			}
+			return next(cmd)
+		})
+	}
+}
+
+// HostOnly requires the caller to host their room.
+func HostOnly(store Store) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return RoomMember(store)(func(cmd *Command) error {
+			if cmd.Room.HostID != cmd.User.ID.String() {
+				return ErrNotHost
+			}
+			return next(cmd)
+		})
+	}
+}
+
+// ModeratorOnly requires the caller to host or co-host their room.
+func ModeratorOnly(store Store) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return RoomMember(store)(func(cmd *Command) error {
+			cmd.Room.Mutex.RLock()
+			allowed := isModerator(cmd.Room, cmd.User.ID.String())
+			cmd.Room.Mutex.RUnlock()
+			if !allowed {
+				return ErrNotModerator
+			}
+			return next(cmd)
+		})
+	}
+}
+
+// RateLimit gives each client address a token bucket refilled at
+// perSecond, holding at most burst tokens; a message without a token is
+// rejected. Buckets are not keyed by the user ID, which the client picks,
+// so reconnecting under new IDs does not refill them. Connections without
+// an address get a bucket each.
+func RateLimit(perSecond float64, burst int) Middleware {
+	limiter := &rateLimiter{perSecond: perSecond, burst: float64(burst), buckets: make(map[string]*bucket)}
+	return func(next HandlerFunc) HandlerFunc {
+		return func(cmd *Command) error {
+			if !limiter.allow(rateLimitKey(cmd.Client), time.Now()) {
+				return &HandlerError{Code: CodeRateLimited, Err: ErrRateLimited}
+			}
+			return next(cmd)
+		}
+	}
+}
+
+func rateLimitKey(client ClientConn) string {
+	if c, ok := client.(AddrConn); ok && c.RemoteAddr() != "" {
+		return "ip:" + c.RemoteAddr()
+	}
+	return fmt.Sprintf("conn:%p", client)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	perSecond float64
+	burst     float64
+	buckets   map[string]*bucket
+	swept     time.Time
+	mutex     sync.Mutex
+}
+
+func (l *rateLimiter) allow(key string, now time.Time) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if now.Sub(l.swept) > rateLimitIdle {
+		// Forget connections that have gone quiet; a refilled bucket is
+		// the same as a new one.
+		for k, b := range l.buckets {
+			if now.Sub(b.last) > rateLimitIdle {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.perSecond
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
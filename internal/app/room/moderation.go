@@ -0,0 +1,340 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// ModerationLogSize caps how many audit entries a room keeps.
+const ModerationLogSize = 200
+
+var (
+	ErrNotModerator   = errors.New("only the host or a co-host can do that")
+	ErrCannotModerate = errors.New("you cannot moderate that user")
+	ErrBanned         = errors.New("you are banned from this room")
+	ErrMuted          = errors.New("you are muted in this room")
+)
+
+type BanUserPayload struct {
+	UserID string `json:"userId"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type UnbanUserPayload struct {
+	UserID string `json:"userId"`
+}
+
+type MuteUserPayload struct {
+	UserID   string `json:"userId"`
+	Duration int    `json:"duration,omitempty"` // seconds, 0 until unmuted
+	Reason   string `json:"reason,omitempty"`
+}
+
+type UnmuteUserPayload struct {
+	UserID string `json:"userId"`
+}
+
+type SetCoHostPayload struct {
+	UserID string `json:"userId"`
+	CoHost bool   `json:"coHost"`
+}
+
+type GetModerationLogPayload struct{}
+
+// ModerationView is the room's moderation state as shown to its moderators.
+type ModerationView struct {
+	Bans  []domain.Ban             `json:"bans"`
+	Mutes map[string]time.Time     `json:"mutes"`
+	Log   []domain.ModerationEntry `json:"log"`
+}
+
+// isModerator reports whether userID hosts or co-hosts the room. Callers
+// must hold at least a read lock on room.Mutex.
+func isModerator(room *domain.Room, userID string) bool {
+	if room.HostID == userID {
+		return true
+	}
+	u, ok := room.Users[userID]
+	return ok && u.IsCoHost
+}
+
+// canModerate checks that actor may act on target: nobody acts on
+// themselves or the host, and only the host acts on co-hosts. Callers must
+// hold at least a read lock on room.Mutex.
+func canModerate(room *domain.Room, actorID, targetID string) error {
+	if !isModerator(room, actorID) {
+		return ErrNotModerator
+	}
+	if targetID == actorID || targetID == room.HostID {
+		return ErrCannotModerate
+	}
+	if u, ok := room.Users[targetID]; ok && u.IsCoHost && actorID != room.HostID {
+		return ErrCannotModerate
+	}
+	return nil
+}
+
+// recordModeration appends to the room's audit log, dropping the oldest
+// entries past ModerationLogSize. Callers must hold room.Mutex.
+func recordModeration(room *domain.Room, entry domain.ModerationEntry) {
+	log := append(room.Moderation.Log, entry)
+	if len(log) > ModerationLogSize {
+		log = log[len(log)-ModerationLogSize:]
+	}
+	room.Moderation.Log = log
+}
+
+// moderate runs act on the actor's room with the room locked, records the
+// entry act returns and saves the room, then tells the room about it. act
+// runs again if the room changed under it.
+func (h *RoomEventHandler) moderate(ctx context.Context, actor *domain.User, act func(room *domain.Room) (domain.ModerationEntry, error)) error {
+	var entry domain.ModerationEntry
+	room, err := h.updateRoom(ctx, actor.RoomID, func(room *domain.Room) error {
+		var err error
+		if entry, err = act(room); err != nil {
+			return err
+		}
+		entry.ActorID, entry.At = actor.ID.String(), time.Now()
+		recordModeration(room, entry)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	h.Broadcast(room.ID, EventModeration, entry)
+	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+	h.roomChanged(ctx, room.ID)
+	return nil
+}
+
+// evict cuts a kicked or banned user off from the room they were removed
+// from: their connection stops getting its broadcasts and their user record
+// is dropped, so coming back takes a new join.
+func (h *RoomEventHandler) evict(ctx context.Context, roomID, userID string) error {
+	user, err := h.Store.GetUser(ctx, userID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	case user.RoomID != roomID:
+		return nil // banned from a room they were not in
+	}
+	if conn, ok := h.sessions.conn(userID); ok {
+		h.unsubscribe(conn)
+	}
+	h.sessions.forget(userID)
+	return h.Store.DeleteUser(ctx, userID)
+}
+
+// BanUser removes the target from the room, if seated, and keeps them out.
+func (h *RoomEventHandler) BanUser(ctx context.Context, actor *domain.User, payload BanUserPayload) error {
+	err := h.moderate(ctx, actor, func(room *domain.Room) (domain.ModerationEntry, error) {
+		if err := canModerate(room, actor.ID.String(), payload.UserID); err != nil {
+			return domain.ModerationEntry{}, err
+		}
+		ban := domain.Ban{
+			UserID:   payload.UserID,
+			BannedBy: actor.ID.String(),
+			Reason:   payload.Reason,
+			At:       time.Now(),
+		}
+		if u, ok := room.Users[payload.UserID]; ok {
+			ban.Username = u.Username
+		} else if _, err := uuid.Parse(payload.UserID); err != nil {
+			return domain.ModerationEntry{}, ErrUserNotFound
+		}
+		if room.Moderation.Bans == nil {
+			room.Moderation.Bans = make(map[string]domain.Ban)
+		}
+		room.Moderation.Bans[payload.UserID] = ban
+		delete(room.Users, payload.UserID)
+		return domain.ModerationEntry{Action: domain.ModBan, TargetID: payload.UserID, Reason: payload.Reason}, nil
+	})
+	if err != nil {
+		return err
+	}
+	return h.evict(ctx, actor.RoomID, payload.UserID)
+}
+
+func (h *RoomEventHandler) UnbanUser(ctx context.Context, actor *domain.User, payload UnbanUserPayload) error {
+	return h.moderate(ctx, actor, func(room *domain.Room) (domain.ModerationEntry, error) {
+		if !isModerator(room, actor.ID.String()) {
+			return domain.ModerationEntry{}, ErrNotModerator
+		}
+		if _, ok := room.Moderation.Bans[payload.UserID]; !ok {
+			return domain.ModerationEntry{}, ErrUserNotFound
+		}
+		delete(room.Moderation.Bans, payload.UserID)
+		return domain.ModerationEntry{Action: domain.ModUnban, TargetID: payload.UserID}, nil
+	})
+}
+
+// MuteUser stops a seated user chatting, for Duration seconds or until
+// unmuted.
+func (h *RoomEventHandler) MuteUser(ctx context.Context, actor *domain.User, payload MuteUserPayload) error {
+	if payload.Duration < 0 {
+		return ErrInvalidSetting
+	}
+	return h.moderate(ctx, actor, func(room *domain.Room) (domain.ModerationEntry, error) {
+		if err := canModerate(room, actor.ID.String(), payload.UserID); err != nil {
+			return domain.ModerationEntry{}, err
+		}
+		if _, ok := room.Users[payload.UserID]; !ok {
+			return domain.ModerationEntry{}, ErrUserNotFound
+		}
+		entry := domain.ModerationEntry{Action: domain.ModMute, TargetID: payload.UserID, Reason: payload.Reason}
+		var until time.Time
+		if payload.Duration > 0 {
+			until = time.Now().Add(time.Duration(payload.Duration) * time.Second)
+			entry.Until = &until
+		}
+		if room.Moderation.Mutes == nil {
+			room.Moderation.Mutes = make(map[string]time.Time)
+		}
+		room.Moderation.Mutes[payload.UserID] = until
+		return entry, nil
+	})
+}
+
+func (h *RoomEventHandler) UnmuteUser(ctx context.Context, actor *domain.User, payload UnmuteUserPayload) error {
+	return h.moderate(ctx, actor, func(room *domain.Room) (domain.ModerationEntry, error) {
+		if err := canModerate(room, actor.ID.String(), payload.UserID); err != nil {
+			return domain.ModerationEntry{}, err
+		}
+		if _, ok := room.Moderation.Mutes[payload.UserID]; !ok {
+			return domain.ModerationEntry{}, ErrUserNotFound
+		}
+		delete(room.Moderation.Mutes, payload.UserID)
+		return domain.ModerationEntry{Action: domain.ModUnmute, TargetID: payload.UserID}, nil
+	})
+}
+
+// SetCoHost grants or takes away a manager's co-host permissions. Only the
+// host may do this.
+func (h *RoomEventHandler) SetCoHost(ctx context.Context, host *domain.User, payload SetCoHostPayload) error {
+	var target *domain.User
+	err := h.moderate(ctx, host, func(room *domain.Room) (domain.ModerationEntry, error) {
+		if room.HostID != host.ID.String() {
+			return domain.ModerationEntry{}, ErrNotHost
+		}
+		if payload.UserID == room.HostID {
+			return domain.ModerationEntry{}, ErrCannotModerate
+		}
+		u, ok := room.Users[payload.UserID]
+		if !ok {
+			return domain.ModerationEntry{}, ErrUserNotFound
+		}
+		if u.IsSpectator() {
+			return domain.ModerationEntry{}, ErrSpectator
+		}
+		u.IsCoHost = payload.CoHost
+		target = u
+		action := domain.ModCoHost
+		if !payload.CoHost {
+			action = domain.ModUncoHost
+		}
+		return domain.ModerationEntry{Action: action, TargetID: payload.UserID}, nil
+	})
+	if err != nil {
+		return err
+	}
+	user, err := h.Store.GetUser(ctx, payload.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	user.IsCoHost = target.IsCoHost
+	return h.Store.SaveUser(ctx, user)
+}
+
+// ModerationLog returns the room's bans, mutes and audit log, oldest first.
+func (h *RoomEventHandler) ModerationLog(room *domain.Room) ModerationView {
+	room.Mutex.RLock()
+	defer room.Mutex.RUnlock()
+	view := ModerationView{
+		Bans:  make([]domain.Ban, 0, len(room.Moderation.Bans)),
+		Mutes: make(map[string]time.Time),
+		Log:   append([]domain.ModerationEntry{}, room.Moderation.Log...),
+	}
+	for _, ban := range room.Moderation.Bans {
+		view.Bans = append(view.Bans, ban)
+	}
+	sort.Slice(view.Bans, func(i, j int) bool { return view.Bans[i].At.Before(view.Bans[j].At) })
+	now := time.Now()
+	for id, until := range room.Moderation.Mutes {
+		if room.Moderation.IsMuted(id, now) {
+			view.Mutes[id] = until
+		}
+	}
+	return view
+}
+
+func (h *RoomEventHandler) HandleBanUser(ctx context.Context, client ClientConn, payload BanUserPayload) error {
+	actor, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	return h.BanUser(ctx, actor, payload)
+}
+
+func (h *RoomEventHandler) HandleUnbanUser(ctx context.Context, client ClientConn, payload UnbanUserPayload) error {
+	actor, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	return h.UnbanUser(ctx, actor, payload)
+}
+
+func (h *RoomEventHandler) HandleMuteUser(ctx context.Context, client ClientConn, payload MuteUserPayload) error {
+	actor, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	return h.MuteUser(ctx, actor, payload)
+}
+
+func (h *RoomEventHandler) HandleUnmuteUser(ctx context.Context, client ClientConn, payload UnmuteUserPayload) error {
+	actor, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	return h.UnmuteUser(ctx, actor, payload)
+}
+
+func (h *RoomEventHandler) HandleSetCoHost(ctx context.Context, client ClientConn, payload SetCoHostPayload) error {
+	host, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	return h.SetCoHost(ctx, host, payload)
+}
+
+func (h *RoomEventHandler) HandleGetModerationLog(ctx context.Context, client ClientConn, payload GetModerationLogPayload) error {
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		return missing(err, ErrNotInRoom)
+	}
+	room, err := h.Store.GetRoom(ctx, user.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	room.Mutex.RLock()
+	allowed := isModerator(room, user.ID.String())
+	room.Mutex.RUnlock()
+	if !allowed {
+		return ErrNotModerator
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventModerationLog,
+		"payload": h.ModerationLog(room),
+	}))
+	return nil
+}
@@ -0,0 +1,60 @@
+package room
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordRoundTrip(t *testing.T) {
+	hash, err := HashPassword("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, passwordScheme+"$") || strings.Contains(hash, "s3cret") {
+		t.Errorf("hash = %q", hash)
+	}
+	if !VerifyPassword(hash, "s3cret") {
+		t.Error("the right password was rejected")
+	}
+	if VerifyPassword(hash, "s3cret ") || VerifyPassword(hash, "") {
+		t.Error("a wrong password was accepted")
+	}
+	again, err := HashPassword("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == hash {
+		t.Error("two hashes of one password share a salt")
+	}
+}
+
+func TestHashEmptyPassword(t *testing.T) {
+	hash, err := HashPassword("")
+	if err != nil || hash != "" {
+		t.Errorf("HashPassword(\"\") = %q, %v, want no password", hash, err)
+	}
+}
+
+func TestVerifyPasswordRejectsMalformedHashes(t *testing.T) {
+	hash, err := HashPassword("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(hash, "$")
+	for name, bad := range map[string]string{
+		"empty":            "",
+		"plain text":       "s3cret",
+		"too few fields":   strings.Join(parts[:3], "$"),
+		"too many fields":  hash + "$x",
+		"other scheme":     "bcrypt$" + strings.Join(parts[1:], "$"),
+		"zero iterations":  strings.Join([]string{parts[0], "0", parts[2], parts[3]}, "$"),
+		"bad iterations":   strings.Join([]string{parts[0], "many", parts[2], parts[3]}, "$"),
+		"bad salt":         strings.Join([]string{parts[0], parts[1], "!!", parts[3]}, "$"),
+		"bad key":          strings.Join([]string{parts[0], parts[1], parts[2], "!!"}, "$"),
+		"other iterations": strings.Join([]string{parts[0], "1000", parts[2], parts[3]}, "$"),
+	} {
+		if VerifyPassword(bad, "s3cret") {
+			t.Errorf("%s: VerifyPassword(%q) accepted the password", name, bad)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package room
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yourusername/TouchlineTactics/internal/storage"
+)
+
+func (h *RoomEventHandler) HandleSearchPlayers(ctx context.Context, client ClientConn, payload storage.PlayerFilter) error {
+	page, err := storage.SearchPlayers(payload)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		return &HandlerError{Code: CodeBadRequest, Err: err}
+	}
+	if err != nil {
+		return &HandlerError{Code: CodeInternal, Err: err}
+	}
+	client.Send(mustMarshal(map[string]interface{}{
+		"type":    EventSearchPlayers,
+		"payload": page,
+	}))
+	return nil
+}
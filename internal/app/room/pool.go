@@ -0,0 +1,47 @@
+package room
+
+import (
+	"context"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// SetPlayerPool stores a validated custom player pool for the host's room.
+// Auctions started in the room then draw from it instead of the global
+// player collection.
+func (h *RoomEventHandler) SetPlayerPool(ctx context.Context, hostID, roomID string, players []domain.Player) error {
+	room, err := h.Store.GetRoom(ctx, roomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	if room.HostID != hostID {
+		return ErrNotHost
+	}
+	if err := h.Store.SavePlayerPool(ctx, roomID, players); err != nil {
+		return err
+	}
+	h.Broadcast(roomID, EventPlayerPoolUpdate, map[string]interface{}{
+		"custom":     true,
+		"numPlayers": len(players),
+	})
+	return nil
+}
+
+// ClearPlayerPool reverts the room to the global player collection.
+func (h *RoomEventHandler) ClearPlayerPool(ctx context.Context, hostID, roomID string) error {
+	room, err := h.Store.GetRoom(ctx, roomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	if room.HostID != hostID {
+		return ErrNotHost
+	}
+	if err := h.Store.DeletePlayerPool(ctx, roomID); err != nil {
+		return err
+	}
+	h.Broadcast(roomID, EventPlayerPoolUpdate, map[string]interface{}{
+		"custom":     false,
+		"numPlayers": 0,
+	})
+	return nil
+}
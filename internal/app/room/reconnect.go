@@ -1,26 +1,266 @@
 package room
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/pkg/logger"
+)
+
+// DefaultReconnectGrace is how long a dropped user's seat is held.
+const DefaultReconnectGrace = 60 * time.Second
+
+var (
+	ErrInvalidReconnectToken = errors.New("invalid reconnect token")
+	ErrSeatReleased          = errors.New("seat is no longer held")
 )
 
+// sessions tracks each user's live connection and the timers that release
+// the seats of dropped users.
+type sessions struct {
+	conns  map[string]ClientConn  // userID -> current connection
+	timers map[string]*time.Timer // userID -> pending release
+	mutex  sync.Mutex
+}
+
+func (s *sessions) attach(userID string, client ClientConn) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conns == nil {
+		s.conns = make(map[string]ClientConn)
+	}
+	s.conns[userID] = client
+	if t, ok := s.timers[userID]; ok {
+		t.Stop()
+		delete(s.timers, userID)
+	}
+}
+
+// conn returns the user's live connection, if they have one.
+func (s *sessions) conn(userID string) (ClientConn, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c, ok := s.conns[userID]
+	return c, ok
+}
+
+func (s *sessions) forget(userID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.conns, userID)
+	if t, ok := s.timers[userID]; ok {
+		t.Stop()
+		delete(s.timers, userID)
+	}
+}
+
+// detach forgets client and schedules release, unless the user has already
+// reconnected on another connection.
+func (s *sessions) detach(userID string, client ClientConn, grace time.Duration, release func()) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if current, ok := s.conns[userID]; ok && current != client {
+		return false
+	}
+	delete(s.conns, userID)
+	if s.timers == nil {
+		s.timers = make(map[string]*time.Timer)
+	}
+	if t, ok := s.timers[userID]; ok {
+		t.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(grace, func() {
+		s.mutex.Lock()
+		current := s.timers[userID] == timer
+		if current {
+			delete(s.timers, userID)
+		}
+		s.mutex.Unlock()
+		if current {
+			release()
+		}
+	})
+	s.timers[userID] = timer
+	return true
+}
+
 func GenerateReconnectToken() string {
 	return uuid.NewString()
 }
 
-func AssociateReconnectToken(store Store, userID, token string) {
-	user, ok := store.GetUser(userID)
-	if !ok {
-		return
+func AssociateReconnectToken(ctx context.Context, store Store, userID, token string) error {
+	user, err := store.GetUser(ctx, userID)
+	if err != nil {
+		return err
 	}
 	user.ReconnectToken = token
-	store.SaveUser(user)
+	return store.SaveUser(ctx, user)
 }
 
-func ValidateReconnectToken(store Store, token string) (*domain.User, bool) {
-	// For MemoryStore, scan all users; for RedisStore, you may need a secondary index or scan
-	// Here, we assume a scan for simplicity
-	// (In production, optimize for Redis with a token->userID map)
-	return nil, false // TODO: Implement for RedisStore
+func ValidateReconnectToken(ctx context.Context, store Store, token string) (*domain.User, error) {
+	if token == "" {
+		return nil, ErrInvalidReconnectToken
+	}
+	user, err := store.GetUserByReconnectToken(ctx, token)
+	if err != nil {
+		return nil, missing(err, ErrInvalidReconnectToken)
+	}
+	return user, nil
+}
+
+// HandleDisconnect holds a dropped user's seat for the reconnect grace window
+// and removes them from the room if they have not come back by then.
+func (h *RoomEventHandler) HandleDisconnect(client ClientConn) {
+	h.lobby.unsubscribe(client)
+	ctx := context.Background()
+	user, err := h.Store.GetUser(ctx, client.ID())
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			logger.Error(fmt.Sprintf("disconnecting %s: %v", client.ID(), err))
+		}
+		return
+	}
+	grace := h.ReconnectGrace
+	if grace == 0 {
+		grace = DefaultReconnectGrace
+	}
+	userID := user.ID.String()
+	release := func() {
+		if err := h.releaseSeat(context.Background(), userID); err != nil {
+			logger.Error(fmt.Sprintf("releasing the seat of %s: %v", userID, err))
+		}
+	}
+	if !h.sessions.detach(userID, client, grace, release) {
+		return // already reconnected elsewhere
+	}
+	room, err := h.setDisconnected(ctx, user, true)
+	if err != nil {
+		logger.Error(fmt.Sprintf("disconnecting %s: %v", userID, err))
+		return
+	}
+	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+}
+
+// releaseSeat removes a user who is still disconnected once their grace
+// window is over.
+func (h *RoomEventHandler) releaseSeat(ctx context.Context, userID string) error {
+	user, err := h.Store.GetUser(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil || !user.Disconnected {
+		return err
+	}
+	if err := h.LeaveRoom(ctx, user); err != nil {
+		return err
+	}
+	return h.Store.DeleteUser(ctx, userID)
+}
+
+// ReplayConn is implemented by connections that can hold back live room
+// events while a resumed session catches up.
+type ReplayConn interface {
+	// HoldEvents queues what Send is given instead of sending it.
+	HoldEvents()
+	// Replay sends msgs, then the queue less the room events numbered up to
+	// seq, and goes back to sending directly.
+	Replay(msgs [][]byte, seq int64)
+}
+
+// resumeSession returns a dropped user to their seat. The caller gets a new
+// reconnect token, the current room snapshot and every broadcast after
+// payload.LastSeq that is still in the event log.
+func (h *RoomEventHandler) resumeSession(ctx context.Context, client ClientConn, payload JoinRoomPayload) error {
+	user, err := ValidateReconnectToken(ctx, h.Store, payload.ReconnectToken)
+	if err != nil {
+		return err
+	}
+	if user.ID.String() != client.ID() {
+		return ErrInvalidUserID
+	}
+	room, err := h.Store.GetRoom(ctx, user.RoomID)
+	if err != nil {
+		return missing(err, ErrRoomNotFound)
+	}
+	room.Mutex.RLock()
+	_, seated := room.Users[client.ID()]
+	room.Mutex.RUnlock()
+	if !seated {
+		return ErrSeatReleased
+	}
+
+	h.sessions.attach(client.ID(), client)
+	user.ReconnectToken = GenerateReconnectToken()
+	if room, err = h.setDisconnected(ctx, user, false); err != nil {
+		return err
+	}
+	// Live events are held back until the missed ones have been sent; those
+	// numbered up to the ack's seq are replayed and dropped from the hold.
+	replay, holds := client.(ReplayConn)
+	if holds {
+		replay.HoldEvents()
+	}
+	h.subscribe(room.ID, client)
+
+	ack := JoinedRoomPayload{
+		UserID:         user.ID.String(),
+		ReconnectToken: user.ReconnectToken,
+		Room:           room,
+		Resumed:        true,
+	}
+	var missed [][]byte
+	if h.Events != nil {
+		ack.Seq = h.Events.Seq(room.ID)
+		var complete bool
+		missed, complete = h.Events.Since(room.ID, payload.LastSeq, ack.Seq)
+		ack.ReplayTruncated = !complete
+	}
+	msgs := append([][]byte{mustMarshal(map[string]interface{}{
+		"type":    EventJoinedRoom,
+		"payload": ack,
+	})}, missed...)
+	if holds {
+		replay.Replay(msgs, ack.Seq)
+	} else {
+		for _, msg := range msgs {
+			client.Send(msg)
+		}
+	}
+	h.Broadcast(room.ID, EventRoomStateUpdate, room)
+	return nil
+}
+
+// subscribe sends the room's broadcasts to client from now on.
+func (h *RoomEventHandler) subscribe(roomID string, client ClientConn) {
+	if h.Subscribe != nil {
+		h.Subscribe(roomID, client)
+	}
+}
+
+// unsubscribe stops the broadcasts to client.
+func (h *RoomEventHandler) unsubscribe(client ClientConn) {
+	if h.Unsubscribe != nil {
+		h.Unsubscribe(client)
+	}
+}
+
+// setDisconnected records the user's connection state on the user and their
+// room entry, returning the updated room.
+func (h *RoomEventHandler) setDisconnected(ctx context.Context, user *domain.User, disconnected bool) (*domain.Room, error) {
+	user.Disconnected = disconnected
+	if err := h.Store.SaveUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return h.updateRoom(ctx, user.RoomID, func(room *domain.Room) error {
+		if seat, ok := room.Users[user.ID.String()]; ok {
+			seat.Disconnected = disconnected
+		}
+		return nil
+	})
 }
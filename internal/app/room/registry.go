@@ -0,0 +1,108 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// Command is one inbound websocket message on its way to a handler.
+// Middleware fills in User and Room as it checks them.
+type Command struct {
+	// Ctx is cancelled when the client's connection closes.
+	Ctx       context.Context
+	Client    ClientConn
+	Type      EventType
+	Payload   json.RawMessage
+	RequestID string
+	User      *domain.User
+	Room      *domain.Room
+}
+
+type HandlerFunc func(cmd *Command) error
+
+// Middleware wraps a handler, e.g. to check the caller before it runs.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+type registration struct {
+	handler    HandlerFunc
+	middleware []Middleware
+}
+
+// Registry maps event types to handlers. Middleware added with Use runs
+// around every command, outermost first, before the command's own.
+type Registry struct {
+	handlers   map[EventType]registration
+	middleware []Middleware
+	mutex      sync.RWMutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[EventType]registration)}
+}
+
+func (r *Registry) Use(middleware ...Middleware) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Handle registers the handler for an event type, replacing any earlier one.
+func (r *Registry) Handle(eventType EventType, handler HandlerFunc, middleware ...Middleware) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handlers[eventType] = registration{handler: handler, middleware: middleware}
+}
+
+// Lookup returns the handler for an event type wrapped in its middleware.
+func (r *Registry) Lookup(eventType EventType) (HandlerFunc, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	reg, ok := r.handlers[eventType]
+	if !ok {
+		return nil, false
+	}
+	handler := reg.handler
+	for i := len(reg.middleware) - 1; i >= 0; i-- {
+		handler = reg.middleware[i](handler)
+	}
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	return handler, true
+}
+
+// Types lists the registered event types.
+func (r *Registry) Types() []EventType {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	types := make([]EventType, 0, len(r.handlers))
+	for t := range r.handlers {
+		types = append(types, t)
+	}
+	return types
+}
+
+// Typed adapts a handler taking a decoded payload of type P.
+func Typed[P any](fn func(ctx context.Context, client ClientConn, payload P) error) HandlerFunc {
+	return func(cmd *Command) error {
+		var payload P
+		if err := decode(cmd.Payload, &payload); err != nil {
+			return err
+		}
+		return fn(cmd.Ctx, cmd.Client, payload)
+	}
+}
+
+// decode reads a message payload; an absent payload leaves v zero.
+func decode(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &HandlerError{Code: CodeBadRequest, Err: err}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package room
 
 import (
 	"errors"
+	"time"
 
 	"github.com/yourusername/TouchlineTactics/internal/domain"
 )
@@ -14,12 +15,12 @@ func NewRoomService() *RoomService {
 
 func (s *RoomService) NewRoom(id, hostID string, settings domain.RoomSettings) *domain.Room {
 	return &domain.Room{
-		ID:       id,
-		HostID:   hostID,
-		Users:    make(map[string]*domain.User),
-		Settings: settings,
-		Status:   domain.RoomWaiting,
-		Chat:     []domain.ChatMessage{},
+		ID:        id,
+		HostID:    hostID,
+		Users:     make(map[string]*domain.User),
+		Settings:  settings,
+		Status:    domain.RoomWaiting,
+		CreatedAt: time.Now(),
 	}
 }
 
@@ -29,7 +30,7 @@ func (s *RoomService) AddUser(room *domain.Room, user *domain.User) error {
 	if _, exists := room.Users[user.ID.String()]; exists {
 		return errors.New("user already in room")
 	}
-	room.Users[user.ID.String()] = user
+	room.Users[user.ID.String()] = user.Seat()
 	return nil
 }
 
@@ -53,9 +54,3 @@ func (s *RoomService) UpdateSettings(room *domain.Room, settings domain.RoomSett
 	defer room.Mutex.Unlock()
 	room.Settings = settings
 }
-
-func (s *RoomService) AddChatMessage(room *domain.Room, msg domain.ChatMessage) {
-	room.Mutex.Lock()
-	defer room.Mutex.Unlock()
-	room.Chat = append(room.Chat, msg)
-}
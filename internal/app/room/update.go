@@ -0,0 +1,39 @@
+package room
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// roomUpdateAttempts bounds how often updateRoom starts over after losing a
+// race to another update of the same room.
+const roomUpdateAttempts = 5
+
+// updateRoom applies change to the latest copy of the room, with room.Mutex
+// held, and saves it. When another update saved the room in between, the
+// save conflicts and change is applied again to a fresh copy, so change
+// must not have effects outside the room. An error from change is returned
+// as is, and the room is left unsaved. Callers must not hold room.Mutex.
+func (h *RoomEventHandler) updateRoom(ctx context.Context, roomID string, change func(room *domain.Room) error) (*domain.Room, error) {
+	for attempt := 0; attempt < roomUpdateAttempts; attempt++ {
+		room, err := h.Store.GetRoom(ctx, roomID)
+		if err != nil {
+			return nil, missing(err, ErrRoomNotFound)
+		}
+		room.Mutex.Lock()
+		err = change(room)
+		if err == nil {
+			err = h.Store.SaveRoom(ctx, room)
+		}
+		room.Mutex.Unlock()
+		if !errors.Is(err, domain.ErrConflict) {
+			if err != nil {
+				return nil, err
+			}
+			return room, nil
+		}
+	}
+	return nil, domain.ErrConflict
+}
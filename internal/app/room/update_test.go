@@ -0,0 +1,111 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"github.com/yourusername/TouchlineTactics/internal/storage"
+)
+
+// racingStore lets another node save the room just before the next save,
+// so that save conflicts.
+type racingStore struct {
+	*storage.MemoryStore
+	race func(ctx context.Context)
+}
+
+func (s *racingStore) SaveRoom(ctx context.Context, room *domain.Room) error {
+	if race := s.race; race != nil {
+		s.race = nil
+		race(ctx)
+	}
+	return s.MemoryStore.SaveRoom(ctx, room)
+}
+
+func TestUpdateRoomReappliesChangeAfterConflict(t *testing.T) {
+	ctx := context.Background()
+	store := &racingStore{MemoryStore: storage.NewMemoryStore()}
+	if err := store.MemoryStore.SaveRoom(ctx, &domain.Room{ID: "r1", Users: map[string]*domain.User{}}); err != nil {
+		t.Fatal(err)
+	}
+	store.race = func(ctx context.Context) {
+		stored, _ := store.GetRoom(ctx, "r1")
+		other := &domain.Room{ID: "r1", Users: map[string]*domain.User{"other": {Username: "other"}}, Version: stored.Version}
+		if err := store.MemoryStore.SaveRoom(ctx, other); err != nil {
+			t.Errorf("racing save: %v", err)
+		}
+	}
+	h := &RoomEventHandler{Store: store}
+
+	calls := 0
+	room, err := h.updateRoom(ctx, "r1", func(room *domain.Room) error {
+		calls++
+		room.Users["me"] = &domain.User{Username: "me"}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("updateRoom: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("change applied %d times, want 2", calls)
+	}
+	stored, _ := store.GetRoom(ctx, "r1")
+	if stored != room {
+		t.Error("returned room is not the stored one")
+	}
+	if _, ok := stored.Users["other"]; !ok {
+		t.Error("the racing update was lost")
+	}
+	if _, ok := stored.Users["me"]; !ok {
+		t.Error("the change was lost")
+	}
+}
+
+func TestUpdateRoomGivesUpAfterRepeatedConflicts(t *testing.T) {
+	ctx := context.Background()
+	store := &racingStore{MemoryStore: storage.NewMemoryStore()}
+	if err := store.MemoryStore.SaveRoom(ctx, &domain.Room{ID: "r1"}); err != nil {
+		t.Fatal(err)
+	}
+	var race func(ctx context.Context)
+	race = func(ctx context.Context) {
+		stored, _ := store.GetRoom(ctx, "r1")
+		_ = store.MemoryStore.SaveRoom(ctx, &domain.Room{ID: "r1", Version: stored.Version})
+		store.race = race
+	}
+	store.race = race
+	h := &RoomEventHandler{Store: store}
+
+	calls := 0
+	_, err := h.updateRoom(ctx, "r1", func(room *domain.Room) error {
+		calls++
+		return nil
+	})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("err = %v, want ErrConflict", err)
+	}
+	if calls != roomUpdateAttempts {
+		t.Errorf("change applied %d times, want %d", calls, roomUpdateAttempts)
+	}
+}
+
+func TestUpdateRoomDoesNotSaveWhenChangeFails(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	if err := store.SaveRoom(ctx, &domain.Room{ID: "r1"}); err != nil {
+		t.Fatal(err)
+	}
+	h := &RoomEventHandler{Store: store}
+	failure := errors.New("refused")
+	if _, err := h.updateRoom(ctx, "r1", func(*domain.Room) error { return failure }); err != failure {
+		t.Errorf("err = %v, want the change's error", err)
+	}
+	if room, _ := store.GetRoom(ctx, "r1"); room.Version != 1 {
+		t.Errorf("version = %d after a failed change, want 1", room.Version)
+	}
+	if _, err := h.updateRoom(ctx, "missing", func(*domain.Room) error { return nil }); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("updating a missing room: err = %v, want ErrRoomNotFound", err)
+	}
+}
@@ -16,6 +16,7 @@ func (s *UserService) NewUser(username, roomID string, isHost bool) *domain.User
 		ID:       uuid.New(),
 		Username: username,
 		RoomID:   roomID,
+		Role:     domain.RoleManager,
 		IsHost:   isHost,
 		Ready:    false,
 	}
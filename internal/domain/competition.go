@@ -0,0 +1,55 @@
+package domain
+
+type CompetitionFormat string
+
+const (
+	CompetitionLeague   CompetitionFormat = "league"
+	CompetitionKnockout CompetitionFormat = "knockout"
+)
+
+// Fixture is one match of a competition. A knockout bye has Bye set, no
+// away manager and the home manager as winner.
+type Fixture struct {
+	ID         string `json:"id"`
+	Round      int    `json:"round"`
+	HomeUserID string `json:"homeUserId"`
+	AwayUserID string `json:"awayUserId,omitempty"`
+	Bye        bool   `json:"bye,omitempty"`
+	Played     bool   `json:"played"`
+	MatchID    string `json:"matchId,omitempty"`
+	HomeGoals  int    `json:"homeGoals"`
+	AwayGoals  int    `json:"awayGoals"`
+	// Penalties is set when a drawn knockout tie was settled by a shootout.
+	Penalties    bool   `json:"penalties,omitempty"`
+	WinnerUserID string `json:"winnerUserId,omitempty"`
+}
+
+type Standing struct {
+	UserID       string `json:"userId"`
+	Played       int    `json:"played"`
+	Won          int    `json:"won"`
+	Drawn        int    `json:"drawn"`
+	Lost         int    `json:"lost"`
+	GoalsFor     int    `json:"goalsFor"`
+	GoalsAgainst int    `json:"goalsAgainst"`
+	Points       int    `json:"points"`
+}
+
+func (s Standing) GoalDifference() int {
+	return s.GoalsFor - s.GoalsAgainst
+}
+
+// Competition is a league or knockout among a room's managers. Round is the
+// round currently being played, starting at 1.
+type Competition struct {
+	RoomID         string            `json:"roomId"`
+	Format         CompetitionFormat `json:"format"`
+	Managers       []string          `json:"managers"`
+	Round          int               `json:"round"`
+	Rounds         int               `json:"rounds"`
+	Fixtures       []Fixture         `json:"fixtures"`
+	Standings      []Standing        `json:"standings,omitempty"`
+	ChampionUserID string            `json:"championUserId,omitempty"`
+	Finished       bool              `json:"finished"`
+	Seed           int64             `json:"seed"`
+}
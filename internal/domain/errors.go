@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by stores. Backend failures, such as a lost connection or
+// a record that will not decode, wrap ErrStorage so callers can tell them
+// from a record that is simply missing.
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflicting update")
+	ErrStorage  = errors.New("storage error")
+)
+
+var ErrChatMessageNotFound = fmt.Errorf("message %w", ErrNotFound)
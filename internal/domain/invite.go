@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Invite lets someone join a room by a short code instead of the room's
+// password.
+type Invite struct {
+	Code      string     `json:"code"`
+	RoomID    string     `json:"roomId"`
+	CreatedBy string     `json:"createdBy"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // nil never expires
+	MaxUses   int        `json:"maxUses,omitempty"`   // 0 is unlimited
+	Uses      int        `json:"uses"`
+	Revoked   bool       `json:"revoked,omitempty"`
+}
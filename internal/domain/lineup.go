@@ -0,0 +1,76 @@
+package domain
+
+// FormationSlot is one starting position in a formation. ID is unique within
+// the formation ("LCB"), Role is the canonical role it asks for ("CB"). X runs
+// from the left touchline (0) to the right (1) and Y from the own goal line
+// (0) towards the opponent's (1).
+type FormationSlot struct {
+	ID   string  `json:"id"`
+	Role string  `json:"role"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+type Formation struct {
+	Name  string          `json:"name"`
+	Slots []FormationSlot `json:"slots"`
+}
+
+// Formations lists the supported formations, slots ordered back to front.
+var Formations = map[string]Formation{
+	"4-4-2": {Name: "4-4-2", Slots: []FormationSlot{
+		{"GK", "GK", 0.5, 0},
+		{"LB", "LB", 0.05, 0.25}, {"LCB", "CB", 0.35, 0.2}, {"RCB", "CB", 0.65, 0.2}, {"RB", "RB", 0.95, 0.25},
+		{"LM", "LM", 0.05, 0.55}, {"LCM", "CM", 0.35, 0.5}, {"RCM", "CM", 0.65, 0.5}, {"RM", "RM", 0.95, 0.55},
+		{"LS", "ST", 0.35, 0.85}, {"RS", "ST", 0.65, 0.85},
+	}},
+	"4-3-3": {Name: "4-3-3", Slots: []FormationSlot{
+		{"GK", "GK", 0.5, 0},
+		{"LB", "LB", 0.05, 0.25}, {"LCB", "CB", 0.35, 0.2}, {"RCB", "CB", 0.65, 0.2}, {"RB", "RB", 0.95, 0.25},
+		{"CDM", "CDM", 0.5, 0.4}, {"LCM", "CM", 0.25, 0.55}, {"RCM", "CM", 0.75, 0.55},
+		{"LW", "LW", 0.1, 0.8}, {"ST", "ST", 0.5, 0.85}, {"RW", "RW", 0.9, 0.8},
+	}},
+	"4-2-3-1": {Name: "4-2-3-1", Slots: []FormationSlot{
+		{"GK", "GK", 0.5, 0},
+		{"LB", "LB", 0.05, 0.25}, {"LCB", "CB", 0.35, 0.2}, {"RCB", "CB", 0.65, 0.2}, {"RB", "RB", 0.95, 0.25},
+		{"LDM", "CDM", 0.35, 0.4}, {"RDM", "CDM", 0.65, 0.4},
+		{"LM", "LM", 0.1, 0.65}, {"CAM", "CAM", 0.5, 0.65}, {"RM", "RM", 0.9, 0.65},
+		{"ST", "ST", 0.5, 0.85},
+	}},
+	"3-5-2": {Name: "3-5-2", Slots: []FormationSlot{
+		{"GK", "GK", 0.5, 0},
+		{"LCB", "CB", 0.25, 0.2}, {"CB", "CB", 0.5, 0.2}, {"RCB", "CB", 0.75, 0.2},
+		{"LWB", "LWB", 0.05, 0.45}, {"LCM", "CM", 0.3, 0.55}, {"CDM", "CDM", 0.5, 0.4}, {"RCM", "CM", 0.7, 0.55}, {"RWB", "RWB", 0.95, 0.45},
+		{"LS", "ST", 0.35, 0.85}, {"RS", "ST", 0.65, 0.85},
+	}},
+	"5-3-2": {Name: "5-3-2", Slots: []FormationSlot{
+		{"GK", "GK", 0.5, 0},
+		{"LWB", "LWB", 0.05, 0.3}, {"LCB", "CB", 0.3, 0.2}, {"CB", "CB", 0.5, 0.2}, {"RCB", "CB", 0.7, 0.2}, {"RWB", "RWB", 0.95, 0.3},
+		{"LCM", "CM", 0.3, 0.5}, {"CM", "CM", 0.5, 0.5}, {"RCM", "CM", 0.7, 0.5},
+		{"LS", "ST", 0.35, 0.85}, {"RS", "ST", 0.65, 0.85},
+	}},
+}
+
+const DefaultFormation = "4-4-2"
+
+// Lineup is a manager's starting eleven, bench and captain. Slots maps a
+// formation slot ID to a player ID from the manager's squad.
+type Lineup struct {
+	RoomID    string            `json:"roomId"`
+	UserID    string            `json:"userId"`
+	Formation string            `json:"formation"`
+	Slots     map[string]string `json:"slots"`
+	Bench     []string          `json:"bench"`
+	Captain   string            `json:"captain,omitempty"`
+}
+
+// TeamRating is the computed strength of a lineup. Rating is Base plus a
+// chemistry bonus; Lines holds the average adjusted Overall per line.
+type TeamRating struct {
+	Rating        float64                  `json:"rating"`
+	Base          float64                  `json:"base"`
+	Chemistry     int                      `json:"chemistry"` // 0-100
+	Links         int                      `json:"links"`     // adjacent pairs sharing club or nationality
+	Lines         map[PositionLine]float64 `json:"lines"`
+	OutOfPosition []string                 `json:"outOfPosition"`
+}
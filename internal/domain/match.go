@@ -0,0 +1,47 @@
+package domain
+
+type MatchSide string
+
+const (
+	HomeSide MatchSide = "home"
+	AwaySide MatchSide = "away"
+)
+
+type MatchEventType string
+
+const (
+	MatchKickOff      MatchEventType = "kickOff"
+	MatchChance       MatchEventType = "chance"
+	MatchGoal         MatchEventType = "goal"
+	MatchYellowCard   MatchEventType = "yellowCard"
+	MatchRedCard      MatchEventType = "redCard"
+	MatchSubstitution MatchEventType = "substitution"
+	MatchHalfTime     MatchEventType = "halfTime"
+	MatchFullTime     MatchEventType = "fullTime"
+)
+
+// MatchEvent is one moment of a simulated match. HomeScore and AwayScore are
+// the score after the event. For substitutions Player is the player coming
+// off and SubPlayer the one coming on.
+type MatchEvent struct {
+	Minute        int            `json:"minute"`
+	Type          MatchEventType `json:"type"`
+	Side          MatchSide      `json:"side,omitempty"`
+	PlayerID      string         `json:"playerId,omitempty"`
+	PlayerName    string         `json:"playerName,omitempty"`
+	SubPlayerID   string         `json:"subPlayerId,omitempty"`
+	SubPlayerName string         `json:"subPlayerName,omitempty"`
+	HomeScore     int            `json:"homeScore"`
+	AwayScore     int            `json:"awayScore"`
+}
+
+type MatchResult struct {
+	ID         string       `json:"id"`
+	RoomID     string       `json:"roomId"`
+	HomeUserID string       `json:"homeUserId"`
+	AwayUserID string       `json:"awayUserId"`
+	HomeGoals  int          `json:"homeGoals"`
+	AwayGoals  int          `json:"awayGoals"`
+	Seed       int64        `json:"seed"`
+	Events     []MatchEvent `json:"events"`
+}
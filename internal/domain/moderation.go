@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+type ModerationAction string
+
+const (
+	ModKick     ModerationAction = "kick"
+	ModBan      ModerationAction = "ban"
+	ModUnban    ModerationAction = "unban"
+	ModMute     ModerationAction = "mute"
+	ModUnmute   ModerationAction = "unmute"
+	ModCoHost   ModerationAction = "cohost"
+	ModUncoHost ModerationAction = "uncohost"
+)
+
+// Ban keeps a user out of a room until the host lifts it.
+type Ban struct {
+	UserID   string    `json:"userId"`
+	Username string    `json:"username,omitempty"`
+	BannedBy string    `json:"bannedBy"`
+	Reason   string    `json:"reason,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// ModerationEntry records one action taken by the host or a co-host.
+type ModerationEntry struct {
+	Action   ModerationAction `json:"action"`
+	ActorID  string           `json:"actorId"`
+	TargetID string           `json:"targetId"`
+	Reason   string           `json:"reason,omitempty"`
+	Until    *time.Time       `json:"until,omitempty"` // end of a timed mute
+	At       time.Time        `json:"at"`
+}
+
+// Moderation is a room's ban list, chat mutes and audit log.
+type Moderation struct {
+	Bans  map[string]Ban       `json:"bans,omitempty"`  // userID -> ban
+	Mutes map[string]time.Time `json:"mutes,omitempty"` // userID -> muted until, zero until unmuted
+	Log   []ModerationEntry    `json:"log,omitempty"`
+}
+
+// IsMuted reports whether the user may not chat at now.
+func (m *Moderation) IsMuted(userID string, now time.Time) bool {
+	until, ok := m.Mutes[userID]
+	return ok && (until.IsZero() || now.Before(until))
+}
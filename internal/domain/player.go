@@ -1,6 +1,7 @@
 package domain
 
 type Player struct {
+	ID            string `bson:"_id,omitempty"`
 	Name          string `bson:"Name"`
 	Age           int    `bson:"Age"`
 	Photo         string `bson:"Photo"`
@@ -17,5 +18,7 @@ type Player struct {
 	WorkRate      string `bson:"Work Rate"`
 	RealFace      string `bson:"Real Face"`
 	Position      string `bson:"Position"`
-	Height        int    `bson:"Height"`
+	// EligiblePositions lists secondary positions the player can also fill.
+	EligiblePositions []string `bson:"Eligible Positions,omitempty"`
+	Height            int      `bson:"Height"`
 }
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+type PositionLine string
+
+const (
+	LineGoalkeeper PositionLine = "GK"
+	LineDefence    PositionLine = "DEF"
+	LineMidfield   PositionLine = "MID"
+	LineForward    PositionLine = "FWD"
+)
+
+// Lines lists the position lines from back to front.
+var Lines = []PositionLine{LineGoalkeeper, LineDefence, LineMidfield, LineForward}
+
+// positionRoles maps every canonical role to its line.
+var positionRoles = map[string]PositionLine{
+	"GK":  LineGoalkeeper,
+	"CB":  LineDefence,
+	"LB":  LineDefence,
+	"RB":  LineDefence,
+	"LWB": LineDefence,
+	"RWB": LineDefence,
+	"CDM": LineMidfield,
+	"CM":  LineMidfield,
+	"CAM": LineMidfield,
+	"LM":  LineMidfield,
+	"RM":  LineMidfield,
+	"LW":  LineForward,
+	"RW":  LineForward,
+	"CF":  LineForward,
+	"ST":  LineForward,
+}
+
+// positionAliases maps side-specific and alternative codes onto canonical roles.
+var positionAliases = map[string]string{
+	"LCB": "CB",
+	"RCB": "CB",
+	"SW":  "CB",
+	"LDM": "CDM",
+	"RDM": "CDM",
+	"DM":  "CDM",
+	"LCM": "CM",
+	"RCM": "CM",
+	"LAM": "CAM",
+	"RAM": "CAM",
+	"AM":  "CAM",
+	"LF":  "CF",
+	"RF":  "CF",
+	"SS":  "CF",
+	"LS":  "ST",
+	"RS":  "ST",
+}
+
+// NormalizePosition returns the canonical role for a raw position code, or
+// "" if the code is unknown.
+func NormalizePosition(raw string) string {
+	code := strings.ToUpper(strings.TrimSpace(raw))
+	if alias, ok := positionAliases[code]; ok {
+		return alias
+	}
+	if _, ok := positionRoles[code]; ok {
+		return code
+	}
+	return ""
+}
+
+// LineOf returns the line of a role, alias or line code.
+func LineOf(position string) (PositionLine, bool) {
+	code := strings.ToUpper(strings.TrimSpace(position))
+	for _, l := range Lines {
+		if PositionLine(code) == l {
+			return l, true
+		}
+	}
+	line, ok := positionRoles[NormalizePosition(code)]
+	return line, ok
+}
+
+// PositionCodes expands a role, alias or line into every raw code that
+// belongs to it, so "CB" yields CB, LCB, RCB and SW and "DEF" yields every
+// defensive code. Unknown codes are returned unchanged.
+func PositionCodes(position string) []string {
+	code := strings.ToUpper(strings.TrimSpace(position))
+	var roles []string
+	if line, ok := LineOf(code); ok && PositionLine(code) == line {
+		for role, l := range positionRoles {
+			if l == line {
+				roles = append(roles, role)
+			}
+		}
+	} else if role := NormalizePosition(code); role != "" {
+		roles = []string{role}
+	} else {
+		return []string{position}
+	}
+	var codes []string
+	for _, role := range roles {
+		codes = append(codes, role)
+		for alias, r := range positionAliases {
+			if r == role {
+				codes = append(codes, alias)
+			}
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Positions returns the player's canonical roles: the primary Position
+// followed by any EligiblePositions, without duplicates.
+func (p Player) Positions() []string {
+	seen := make(map[string]bool)
+	var roles []string
+	raw := append(strings.Split(p.Position, ","), p.EligiblePositions...)
+	for _, r := range raw {
+		role := NormalizePosition(r)
+		if role == "" || seen[role] {
+			continue
+		}
+		seen[role] = true
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+// PlayerLines returns the distinct lines the player can play in.
+func (p Player) PlayerLines() []PositionLine {
+	seen := make(map[PositionLine]bool)
+	var lines []PositionLine
+	for _, role := range p.Positions() {
+		line := positionRoles[role]
+		if !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// CanPlay reports whether the player is eligible for a role or a whole line.
+func (p Player) CanPlay(position string) bool {
+	code := strings.ToUpper(strings.TrimSpace(position))
+	if line, ok := LineOf(code); ok && PositionLine(code) == line {
+		for _, l := range p.PlayerLines() {
+			if l == line {
+				return true
+			}
+		}
+		return false
+	}
+	want := NormalizePosition(code)
+	for _, role := range p.Positions() {
+		if role == want {
+			return true
+		}
+	}
+	return false
+}
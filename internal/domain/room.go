@@ -7,28 +7,85 @@ import (
 
 type RoomStatus string
 
+// Rooms move WAITING -> AUCTION -> SQUAD_BUILDING -> COMPETITION -> FINISHED.
+// PAUSED and CANCELLED can be entered from the active phases; see the
+// transition table in app/room/lifecycle.go.
 const (
-	RoomWaiting    RoomStatus = "WAITING"
-	RoomInProgress RoomStatus = "IN_PROGRESS"
-	RoomFinished   RoomStatus = "FINISHED"
-	RoomPaused     RoomStatus = "PAUSED"
-	RoomCancelled  RoomStatus = "CANCELLED"
+	RoomWaiting       RoomStatus = "WAITING"
+	RoomAuction       RoomStatus = "AUCTION"
+	RoomSquadBuilding RoomStatus = "SQUAD_BUILDING"
+	RoomCompetition   RoomStatus = "COMPETITION"
+	RoomFinished      RoomStatus = "FINISHED"
+	RoomPaused        RoomStatus = "PAUSED"
+	RoomCancelled     RoomStatus = "CANCELLED"
 )
 
 type RoomSettings struct {
-	Password string
-	Private  bool
-	GameMode string
-	Timer    int
-	MaxUsers int
-	Custom   map[string]interface{}
+	// PasswordHash is never sent to clients; see room.HashPassword.
+	PasswordHash string `json:"-"`
+	Private      bool
+	GameMode     string
+	Timer        int
+	MaxUsers     int
+	MinUsers     int // managers needed to start the auction, default 2
+	// MaxSpectators caps watchers separately from MaxUsers; SpectatorChat
+	// lets them post in chat.
+	MaxSpectators int
+	SpectatorChat bool
+	// PoolProfile names the auction.PoolProfiles entry used to draw players;
+	// a non-zero PoolSeed makes the drawn pool reproducible.
+	PoolProfile string
+	PoolSeed    int64
+	// SquadQuota caps how many players of each line a manager may buy.
+	SquadQuota map[PositionLine]int
+	// Budget is what each manager may spend in the auction; 0 is unlimited.
+	Budget int
+	// AutoStart starts the auction AutoStartDelay seconds after every
+	// manager is ready (default 10).
+	AutoStart      bool
+	AutoStartDelay int
+
+	// AllowLinks lets chat messages contain links; DisableAuctionChat turns
+	// chat off while the auction runs.
+	AllowLinks         bool
+	DisableAuctionChat bool
+	Custom             map[string]interface{}
 }
 
+type ChatKind string
+
+const (
+	ChatUser    ChatKind = ""
+	ChatSystem  ChatKind = "system"  // posted by the server, no UserID
+	ChatWhisper ChatKind = "whisper" // seen only by the sender and To
+)
+
+// ChatMessage IDs count up from 1 within each room; whispers are not kept
+// and have no ID.
 type ChatMessage struct {
+	ID        int64
+	Kind      ChatKind `json:",omitempty"`
 	UserID    string
 	Username  string
 	Message   string
 	Timestamp time.Time
+	To        string              `json:",omitempty"`
+	Mentions  []string            `json:",omitempty"` // userIDs
+	Reactions map[string][]string `json:",omitempty"` // emoji -> userIDs
+}
+
+// ChatCursor selects chat messages older than a message ID and/or a time;
+// zero fields do not constrain.
+type ChatCursor struct {
+	BeforeID   int64
+	BeforeTime time.Time
+}
+
+func (c ChatCursor) Admits(msg ChatMessage) bool {
+	if c.BeforeID > 0 && msg.ID >= c.BeforeID {
+		return false
+	}
+	return c.BeforeTime.IsZero() || msg.Timestamp.Before(c.BeforeTime)
 }
 
 type Room struct {
@@ -37,7 +94,15 @@ type Room struct {
 	Users        map[string]*User
 	Settings     RoomSettings
 	Status       RoomStatus
-	Chat         []ChatMessage
+	PausedFrom   RoomStatus // status to resume to while PAUSED
+	CreatedAt    time.Time
 	LastActivity time.Time
-	Mutex        sync.RWMutex
+	Mutex        sync.RWMutex `json:"-"`
+
+	// Moderation is for the host's eyes only and is left out of room
+	// snapshots.
+	Moderation Moderation `json:"-"`
+	// Version counts the room's saves. Stores refuse to save over a newer
+	// version with ErrConflict.
+	Version int64
 }
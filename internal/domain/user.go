@@ -2,11 +2,39 @@ package domain
 
 import "github.com/google/uuid"
 
+type UserRole string
+
+const (
+	RoleManager UserRole = "manager"
+	// RoleSpectator watches the room without a manager seat: no bids, no
+	// ready state and chat only if the host allows it.
+	RoleSpectator UserRole = "spectator"
+)
+
 type User struct {
 	ID             uuid.UUID
 	Username       string
 	RoomID         string
+	Role           UserRole
 	IsHost         bool
 	Ready          bool
-	ReconnectToken string
+	ReconnectToken string `json:"-"` // sent only to its own user
+	// Disconnected is set while the user's seat is held after a dropped
+	// connection.
+	Disconnected bool
+
+	// IsCoHost lets the user kick, ban and mute on the host's behalf.
+	IsCoHost bool
+}
+
+// Seat returns the copy of u kept in its room's Users, which is shown to
+// everyone in the room and so has no reconnect token.
+func (u *User) Seat() *User {
+	seat := *u
+	seat.ReconnectToken = ""
+	return &seat
+}
+
+func (u *User) IsSpectator() bool {
+	return u.Role == RoleSpectator
 }
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/TouchlineTactics/internal/app/room"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// ResolveInviteHandler serves GET /invites/:code. It tells a client which
+// room a code admits to, so it can send joinRoom with the roomId and
+// inviteCode; the room password is never exposed.
+func ResolveInviteHandler(handler *room.RoomEventHandler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		summary, err := handler.InviteSummary(c.UserContext(), c.Params("code"))
+		switch {
+		case errors.Is(err, room.ErrInviteNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrStorage):
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		case err != nil:
+			return c.Status(fiber.StatusGone).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(summary)
+	}
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/TouchlineTactics/internal/storage"
+)
+
+// SearchPlayersHandler serves GET /players. Filters are taken from the query
+// string, e.g. /players?position=ST&position=CF&minOverall=80&sortBy=value.
+func SearchPlayersHandler(c *fiber.Ctx) error {
+	var filter storage.PlayerFilter
+	if err := c.QueryParser(&filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	page, err := storage.SearchPlayers(filter)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(page)
+}
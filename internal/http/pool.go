@@ -0,0 +1,87 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/TouchlineTactics/internal/app/auction"
+	"github.com/yourusername/TouchlineTactics/internal/app/room"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// ReconnectTokenHeader carries the caller's reconnect token on requests
+// only the room host may make. User IDs are visible to everyone in a room,
+// so the token is what proves the caller is the host.
+const ReconnectTokenHeader = "X-Reconnect-Token"
+
+// UploadPlayerPoolHandler serves POST /rooms/:roomId/pool with the host's
+// reconnect token in ReconnectTokenHeader. The body is a JSON array of
+// players or, with a text/csv content type or ?format=csv, a CSV file with
+// a header row.
+func UploadPlayerPoolHandler(handler *room.RoomEventHandler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format := c.Query("format")
+		if format == "" {
+			format = "json"
+			if strings.Contains(c.Get(fiber.HeaderContentType), "csv") {
+				format = "csv"
+			}
+		}
+		players, err := auction.ParsePlayerPool(format, bytes.NewReader(c.Body()))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		caller, err := room.ValidateReconnectToken(c.UserContext(), handler.Store, c.Get(ReconnectTokenHeader))
+		if err != nil {
+			return poolError(c, err)
+		}
+		if err := handler.SetPlayerPool(c.UserContext(), caller.ID.String(), c.Params("roomId"), players); err != nil {
+			return poolError(c, err)
+		}
+		return c.JSON(fiber.Map{"numPlayers": len(players)})
+	}
+}
+
+// GetPlayerPoolHandler serves GET /rooms/:roomId/pool.
+func GetPlayerPoolHandler(handler *room.RoomEventHandler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		players, err := handler.Store.GetPlayerPool(c.UserContext(), c.Params("roomId"))
+		if errors.Is(err, domain.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "room has no custom player pool"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(players)
+	}
+}
+
+// DeletePlayerPoolHandler serves DELETE /rooms/:roomId/pool with the host's
+// reconnect token in ReconnectTokenHeader.
+func DeletePlayerPoolHandler(handler *room.RoomEventHandler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		caller, err := room.ValidateReconnectToken(c.UserContext(), handler.Store, c.Get(ReconnectTokenHeader))
+		if err != nil {
+			return poolError(c, err)
+		}
+		if err := handler.ClearPlayerPool(c.UserContext(), caller.ID.String(), c.Params("roomId")); err != nil {
+			return poolError(c, err)
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func poolError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, room.ErrRoomNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, room.ErrInvalidReconnectToken):
+		status = fiber.StatusUnauthorized
+	case errors.Is(err, room.ErrNotHost):
+		status = fiber.StatusForbidden
+	}
+	return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+}
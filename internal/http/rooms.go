@@ -0,0 +1,28 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/TouchlineTactics/internal/app/room"
+)
+
+// ListRoomsHandler serves GET /rooms, the public room browser. Filters and
+// paging come from the query string, e.g.
+// /rooms?gameMode=classic&status=WAITING&freeSeats=1&sortBy=players&limit=20&offset=40.
+func ListRoomsHandler(handler *room.RoomEventHandler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var query room.RoomQuery
+		if err := c.QueryParser(&query); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		page, err := handler.SearchRooms(c.UserContext(), query)
+		if errors.Is(err, room.ErrInvalidRoomQuery) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(page)
+	}
+}
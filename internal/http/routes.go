@@ -8,6 +8,10 @@ import (
 
 func SetupRoutes(app *fiber.App, hub *ws.Hub, dispatcher *room.EventDispatcher) {
 	app.Get("/ws", ws.WebSocketHandler(hub, dispatcher))
-	// Add HTTP endpoints here, e.g.:
-	// app.Get("/rooms", ListRoomsHandler)
+	app.Get("/players", SearchPlayersHandler)
+	app.Post("/rooms/:roomId/pool", UploadPlayerPoolHandler(dispatcher.Handler))
+	app.Get("/rooms/:roomId/pool", GetPlayerPoolHandler(dispatcher.Handler))
+	app.Delete("/rooms/:roomId/pool", DeletePlayerPoolHandler(dispatcher.Handler))
+	app.Get("/invites/:code", ResolveInviteHandler(dispatcher.Handler))
+	app.Get("/rooms", ListRoomsHandler(dispatcher.Handler))
 }
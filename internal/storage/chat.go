@@ -0,0 +1,26 @@
+package storage
+
+import "github.com/yourusername/TouchlineTactics/internal/domain"
+
+const (
+	// ChatHistoryLimit is how many of its latest chat messages a room keeps.
+	ChatHistoryLimit = 500
+
+	chatUpdateAttempts = 5
+)
+
+// pageChat returns the newest limit messages the cursor admits, oldest
+// first, and whether older ones remain. history must be in ID order.
+func pageChat(history []domain.ChatMessage, cursor domain.ChatCursor, limit int) ([]domain.ChatMessage, bool) {
+	end := len(history)
+	for end > 0 && !cursor.Admits(history[end-1]) {
+		end--
+	}
+	start := end - limit
+	if start < 0 {
+		start = 0
+	}
+	page := make([]domain.ChatMessage, end-start)
+	copy(page, history[start:end])
+	return page, start > 0
+}
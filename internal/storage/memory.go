@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"context"
+	"sort"
 	"sync"
 
 	"github.com/yourusername/TouchlineTactics/internal/domain"
@@ -9,62 +11,284 @@ import (
 type MemoryStore struct {
 	Rooms map[string]*domain.Room
 	Users map[string]*domain.User
-	Mutex sync.RWMutex
+	Pools map[string][]domain.Player
+	// Teams and Lineups are keyed by roomID, then userID.
+	Teams   map[string]map[string][]domain.Player
+	Lineups map[string]map[string]*domain.Lineup
+	Invites map[string]*domain.Invite // code -> invite
+	// Chat holds each room's latest ChatHistoryLimit messages.
+	Chat    map[string][]domain.ChatMessage
+	chatIDs map[string]int64 // roomID -> last message ID
+	// Tokens indexes users by reconnect token.
+	Tokens     map[string]string
+	userTokens map[string]string // userID -> indexed token
+	Mutex      sync.RWMutex
 }
 
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
-		Rooms: make(map[string]*domain.Room),
-		Users: make(map[string]*domain.User),
+		Rooms:      make(map[string]*domain.Room),
+		Users:      make(map[string]*domain.User),
+		Pools:      make(map[string][]domain.Player),
+		Teams:      make(map[string]map[string][]domain.Player),
+		Lineups:    make(map[string]map[string]*domain.Lineup),
+		Invites:    make(map[string]*domain.Invite),
+		Chat:       make(map[string][]domain.ChatMessage),
+		chatIDs:    make(map[string]int64),
+		Tokens:     make(map[string]string),
+		userTokens: make(map[string]string),
 	}
 }
 
 // Room operations
-func (s *MemoryStore) GetRoom(id string) (*domain.Room, bool) {
+func (s *MemoryStore) GetRoom(ctx context.Context, id string) (*domain.Room, error) {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
 	r, ok := s.Rooms[id]
-	return r, ok
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return r, nil
 }
 
-func (s *MemoryStore) SaveRoom(room *domain.Room) {
+// SaveRoom stores room and bumps its version. Rooms are shared by pointer,
+// so it only conflicts when a different copy of the room, or none, was
+// stored since room was read.
+func (s *MemoryStore) SaveRoom(ctx context.Context, room *domain.Room) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
+	var version int64
+	current, ok := s.Rooms[room.ID]
+	if ok {
+		version = current.Version
+	}
+	if current != room && version != room.Version {
+		return domain.ErrConflict
+	}
+	room.Version++
 	s.Rooms[room.ID] = room
+	return nil
 }
 
-func (s *MemoryStore) DeleteRoom(id string) {
+func (s *MemoryStore) DeleteRoom(ctx context.Context, id string) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 	delete(s.Rooms, id)
+	delete(s.Pools, id)
+	delete(s.Teams, id)
+	delete(s.Lineups, id)
+	delete(s.Chat, id)
+	delete(s.chatIDs, id)
+	for code, invite := range s.Invites {
+		if invite.RoomID == id {
+			delete(s.Invites, code)
+		}
+	}
+	return nil
 }
 
 // User operations
-func (s *MemoryStore) GetUser(id string) (*domain.User, bool) {
+func (s *MemoryStore) GetUser(ctx context.Context, id string) (*domain.User, error) {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
 	u, ok := s.Users[id]
-	return u, ok
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return u, nil
 }
 
-func (s *MemoryStore) SaveUser(user *domain.User) {
+func (s *MemoryStore) SaveUser(ctx context.Context, user *domain.User) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
-	s.Users[user.ID.String()] = user
+	id := user.ID.String()
+	s.Users[id] = user
+	if old := s.userTokens[id]; old != user.ReconnectToken {
+		delete(s.Tokens, old)
+	}
+	if user.ReconnectToken != "" {
+		s.Tokens[user.ReconnectToken] = id
+		s.userTokens[id] = user.ReconnectToken
+	}
+	return nil
 }
 
-func (s *MemoryStore) DeleteUser(id string) {
+func (s *MemoryStore) DeleteUser(ctx context.Context, id string) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 	delete(s.Users, id)
+	delete(s.Tokens, s.userTokens[id])
+	delete(s.userTokens, id)
+	return nil
 }
 
-func (s *MemoryStore) ListRooms() []*domain.Room {
+func (s *MemoryStore) GetUserByReconnectToken(ctx context.Context, token string) (*domain.User, error) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	u, ok := s.Users[s.Tokens[token]]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return u, nil
+}
+
+func (s *MemoryStore) ListRooms(ctx context.Context) ([]*domain.Room, error) {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
 	rooms := make([]*domain.Room, 0, len(s.Rooms))
 	for _, room := range s.Rooms {
 		rooms = append(rooms, room)
 	}
-	return rooms
+	return rooms, nil
+}
+
+// Custom player pool operations
+func (s *MemoryStore) GetPlayerPool(ctx context.Context, roomID string) ([]domain.Player, error) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	p, ok := s.Pools[roomID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return p, nil
+}
+
+func (s *MemoryStore) SavePlayerPool(ctx context.Context, roomID string, players []domain.Player) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.Pools[roomID] = players
+	return nil
+}
+
+func (s *MemoryStore) DeletePlayerPool(ctx context.Context, roomID string) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	delete(s.Pools, roomID)
+	return nil
+}
+
+// Team and lineup operations
+func (s *MemoryStore) GetTeam(ctx context.Context, roomID, userID string) ([]domain.Player, error) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	return append([]domain.Player{}, s.Teams[roomID][userID]...), nil
+}
+
+func (s *MemoryStore) AddPlayerToTeam(ctx context.Context, roomID, userID string, player domain.Player) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	if s.Teams[roomID] == nil {
+		s.Teams[roomID] = make(map[string][]domain.Player)
+	}
+	s.Teams[roomID][userID] = append(s.Teams[roomID][userID], player)
+	return nil
+}
+
+func (s *MemoryStore) GetLineup(ctx context.Context, roomID, userID string) (*domain.Lineup, error) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	l, ok := s.Lineups[roomID][userID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return l, nil
+}
+
+func (s *MemoryStore) SaveLineup(ctx context.Context, lineup *domain.Lineup) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	if s.Lineups[lineup.RoomID] == nil {
+		s.Lineups[lineup.RoomID] = make(map[string]*domain.Lineup)
+	}
+	s.Lineups[lineup.RoomID][lineup.UserID] = lineup
+	return nil
+}
+
+// Invite operations
+func (s *MemoryStore) GetInvite(ctx context.Context, code string) (*domain.Invite, error) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	invite, ok := s.Invites[code]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *invite // uses are counted under the lock
+	return &copied, nil
+}
+
+func (s *MemoryStore) SaveInvite(ctx context.Context, invite *domain.Invite) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	stored := *invite
+	if current, ok := s.Invites[invite.Code]; ok {
+		stored.Uses = current.Uses // counted by AddInviteUse alone
+	}
+	s.Invites[invite.Code] = &stored
+	return nil
+}
+
+func (s *MemoryStore) AddInviteUse(ctx context.Context, code string, delta int) (int, error) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	invite, ok := s.Invites[code]
+	if !ok {
+		return 0, domain.ErrNotFound
+	}
+	invite.Uses += delta
+	return invite.Uses, nil
+}
+
+func (s *MemoryStore) DeleteInvite(ctx context.Context, code string) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	delete(s.Invites, code)
+	return nil
+}
+
+func (s *MemoryStore) ListInvites(ctx context.Context, roomID string) ([]*domain.Invite, error) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	var invites []*domain.Invite
+	for _, invite := range s.Invites {
+		if invite.RoomID == roomID {
+			copied := *invite
+			invites = append(invites, &copied)
+		}
+	}
+	return invites, nil
+}
+
+// Chat operations
+func (s *MemoryStore) AppendChatMessage(ctx context.Context, roomID string, msg *domain.ChatMessage) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.chatIDs[roomID]++
+	msg.ID = s.chatIDs[roomID]
+	history := append(s.Chat[roomID], *msg)
+	if len(history) > ChatHistoryLimit {
+		history = history[len(history)-ChatHistoryLimit:]
+	}
+	s.Chat[roomID] = history
+	return nil
+}
+
+func (s *MemoryStore) GetChatHistory(ctx context.Context, roomID string, cursor domain.ChatCursor, limit int) ([]domain.ChatMessage, bool, error) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	page, more := pageChat(s.Chat[roomID], cursor, limit)
+	return page, more, nil
+}
+
+func (s *MemoryStore) UpdateChatMessage(ctx context.Context, roomID string, id int64, update func(*domain.ChatMessage) error) (domain.ChatMessage, error) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	history := s.Chat[roomID]
+	i := sort.Search(len(history), func(i int) bool { return history[i].ID >= id })
+	if i == len(history) || history[i].ID != id {
+		return domain.ChatMessage{}, domain.ErrChatMessageNotFound
+	}
+	if err := update(&history[i]); err != nil {
+		return domain.ChatMessage{}, err
+	}
+	return history[i], nil
 }
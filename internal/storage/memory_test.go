@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// copyRoom returns a copy of room such as another node would hold after
+// reading it.
+func copyRoom(room *domain.Room) *domain.Room {
+	return &domain.Room{
+		ID:       room.ID,
+		HostID:   room.HostID,
+		Users:    map[string]*domain.User{},
+		Settings: room.Settings,
+		Status:   room.Status,
+		Version:  room.Version,
+	}
+}
+
+func TestMemoryStoreSaveRoomRejectsStaleCopies(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	if err := s.SaveRoom(ctx, &domain.Room{ID: "r1", Status: domain.RoomWaiting}); err != nil {
+		t.Fatalf("creating: %v", err)
+	}
+	stored, _ := s.GetRoom(ctx, "r1")
+	first, second := copyRoom(stored), copyRoom(stored)
+
+	first.Status = domain.RoomAuction
+	if err := s.SaveRoom(ctx, first); err != nil {
+		t.Fatalf("saving the first copy: %v", err)
+	}
+	second.Status = domain.RoomCancelled
+	if err := s.SaveRoom(ctx, second); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("saving the stale copy: err = %v, want ErrConflict", err)
+	}
+
+	room, _ := s.GetRoom(ctx, "r1")
+	if room.Status != domain.RoomAuction || room.Version != 2 {
+		t.Errorf("stored room is %s at version %d, want AUCTION at version 2", room.Status, room.Version)
+	}
+	// A room created elsewhere under the same ID is stale too.
+	if err := s.SaveRoom(ctx, &domain.Room{ID: "r1"}); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("saving a new room over r1: err = %v, want ErrConflict", err)
+	}
+	// The stored copy itself saves, whatever its version.
+	if err := s.SaveRoom(ctx, room); err != nil {
+		t.Errorf("saving the stored room: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// A migration upgrades a stored record, decoded as a generic document, from
+// the schema version equal to its index in the list to the next one. The
+// current schema version of a record kind is the length of its list, so a
+// change to a record's shape is made by appending a migration.
+type migration func(doc map[string]interface{}) error
+
+var (
+	roomMigrations = []migration{migrateLegacyRoom}
+	userMigrations = []migration{migrateLegacyUser}
+	chatMigrations = []migration{migrateLegacyChatMessage}
+)
+
+// decodeRecord reads a stored record into v, running the migrations from
+// the record's schema version to the current one first. Records without a
+// schema field predate versioning and are version 0. Migrated records are
+// only rewritten when next saved.
+func decodeRecord(data []byte, migrations []migration, v interface{}) error {
+	var head struct {
+		Schema int `json:"schema"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return err
+	}
+	current := len(migrations)
+	switch {
+	case head.Schema == current:
+		return json.Unmarshal(data, v)
+	case head.Schema > current || head.Schema < 0:
+		return fmt.Errorf("unknown schema version %d, expected at most %d", head.Schema, current)
+	}
+	// Numbers are kept as written so that large IDs and seeds survive.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	for version := head.Schema; version < current; version++ {
+		if err := migrations[version](doc); err != nil {
+			return fmt.Errorf("migrating from schema version %d: %w", version, err)
+		}
+	}
+	doc["schema"] = current
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// renameKeys renames the keys of doc found in names.
+func renameKeys(doc map[string]interface{}, names map[string]string) {
+	for from, to := range names {
+		if v, ok := doc[from]; ok {
+			delete(doc, from)
+			doc[to] = v
+		}
+	}
+}
+
+// Version 0 records are the domain types encoded as they were, with Go
+// field names for keys.
+
+var (
+	legacyRoomKeys = map[string]string{
+		"ID": "id", "HostID": "hostId", "Users": "users", "Settings": "settings",
+		"Status": "status", "PausedFrom": "pausedFrom", "CreatedAt": "createdAt",
+		"LastActivity": "lastActivity", "Version": "version",
+	}
+	legacySettingsKeys = map[string]string{
+		"Private": "private", "GameMode": "gameMode", "Timer": "timer",
+		"MaxUsers": "maxUsers", "MinUsers": "minUsers", "MaxSpectators": "maxSpectators",
+		"SpectatorChat": "spectatorChat", "PoolProfile": "poolProfile", "PoolSeed": "poolSeed",
+		"SquadQuota": "squadQuota", "AutoStart": "autoStart", "AutoStartDelay": "autoStartDelay",
+		"AllowLinks": "allowLinks", "DisableAuctionChat": "disableAuctionChat", "Custom": "custom",
+	}
+	legacyUserKeys = map[string]string{
+		"ID": "id", "Username": "username", "RoomID": "roomId", "Role": "role",
+		"IsHost": "isHost", "IsCoHost": "isCoHost", "Ready": "ready",
+		"ReconnectToken": "reconnectToken", "Disconnected": "disconnected",
+	}
+	legacyChatKeys = map[string]string{
+		"ID": "id", "Kind": "kind", "UserID": "userId", "Username": "username",
+		"Message": "message", "Timestamp": "timestamp", "To": "to",
+		"Mentions": "mentions", "Reactions": "reactions",
+	}
+)
+
+// migrateLegacyRoom also drops the encoded mutex and moves the password
+// hash, which was stored beside the room, into its settings.
+func migrateLegacyRoom(doc map[string]interface{}) error {
+	renameKeys(doc, legacyRoomKeys)
+	delete(doc, "Mutex")
+	settings, _ := doc["settings"].(map[string]interface{})
+	if settings == nil {
+		settings = map[string]interface{}{}
+		doc["settings"] = settings
+	}
+	renameKeys(settings, legacySettingsKeys)
+	if hash, ok := doc["passwordHash"]; ok {
+		settings["passwordHash"] = hash
+		delete(doc, "passwordHash")
+	}
+	users, _ := doc["users"].(map[string]interface{})
+	for _, u := range users {
+		user, ok := u.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("malformed user in room %v", doc["id"])
+		}
+		renameKeys(user, legacyUserKeys)
+	}
+	return nil
+}
+
+func migrateLegacyUser(doc map[string]interface{}) error {
+	renameKeys(doc, legacyUserKeys)
+	return nil
+}
+
+func migrateLegacyChatMessage(doc map[string]interface{}) error {
+	renameKeys(doc, legacyChatKeys)
+	return nil
+}
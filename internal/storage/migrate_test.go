@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// Version 0 fixtures: the domain types as they used to be encoded, with Go
+// field names for keys, the room's mutex and the password hash beside the
+// room.
+const (
+	legacyRoom = `{
+		"ID": "r1",
+		"HostID": "6f1c7a9e-3d2b-4c5a-8e9f-0a1b2c3d4e5f",
+		"Users": {
+			"6f1c7a9e-3d2b-4c5a-8e9f-0a1b2c3d4e5f": {
+				"ID": "6f1c7a9e-3d2b-4c5a-8e9f-0a1b2c3d4e5f", "Username": "alice", "RoomID": "r1",
+				"Role": "manager", "IsHost": true, "Ready": true,
+				"ReconnectToken": "token-a", "Disconnected": false
+			},
+			"0d9e8f7a-6b5c-4d3e-9f2a-1b0c9d8e7f6a": {
+				"ID": "0d9e8f7a-6b5c-4d3e-9f2a-1b0c9d8e7f6a", "Username": "bob", "RoomID": "r1",
+				"Role": "spectator", "IsCoHost": true, "Disconnected": true
+			}
+		},
+		"Settings": {
+			"Private": true, "GameMode": "draft", "Timer": 30, "MaxUsers": 8, "MinUsers": 2,
+			"MaxSpectators": 4, "SpectatorChat": true, "PoolProfile": "stars",
+			"PoolSeed": 9007199254740993, "SquadQuota": {"DEF": 4, "MID": 4},
+			"AutoStart": true, "AutoStartDelay": 15, "AllowLinks": true,
+			"DisableAuctionChat": true, "Custom": {"theme": "dark"}
+		},
+		"passwordHash": "hash",
+		"Status": "PAUSED",
+		"PausedFrom": "AUCTION",
+		"CreatedAt": "2024-03-01T12:00:00Z",
+		"LastActivity": "2024-03-01T12:30:00Z",
+		"Mutex": {},
+		"Version": 7
+	}`
+	legacyUser = `{
+		"ID": "6f1c7a9e-3d2b-4c5a-8e9f-0a1b2c3d4e5f", "Username": "alice", "RoomID": "r1",
+		"Role": "manager", "IsHost": true, "IsCoHost": false, "Ready": true,
+		"ReconnectToken": "token-a", "Disconnected": true
+	}`
+	legacyChatMessage = `{
+		"ID": 12, "Kind": "system", "UserID": "", "Username": "", "Message": "alice bought Messi for 90",
+		"Timestamp": "2024-03-01T12:05:00Z", "Mentions": ["0d9e8f7a-6b5c-4d3e-9f2a-1b0c9d8e7f6a"],
+		"Reactions": {"👍": ["6f1c7a9e-3d2b-4c5a-8e9f-0a1b2c3d4e5f"]}
+	}`
+)
+
+func TestDecodeLegacyRoom(t *testing.T) {
+	var record roomRecord
+	if err := decodeRecord([]byte(legacyRoom), roomMigrations, &record); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if record.Schema != len(roomMigrations) {
+		t.Errorf("schema = %d, want %d", record.Schema, len(roomMigrations))
+	}
+	room, err := record.toDomain()
+	if err != nil {
+		t.Fatalf("converting: %v", err)
+	}
+
+	if room.ID != "r1" || room.HostID != "6f1c7a9e-3d2b-4c5a-8e9f-0a1b2c3d4e5f" || room.Version != 7 {
+		t.Errorf("room = %s hosted by %s at version %d", room.ID, room.HostID, room.Version)
+	}
+	if room.Status != domain.RoomPaused || room.PausedFrom != domain.RoomAuction {
+		t.Errorf("status = %s from %s, want PAUSED from AUCTION", room.Status, room.PausedFrom)
+	}
+	if want := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC); !room.LastActivity.Equal(want) {
+		t.Errorf("last activity = %v, want %v", room.LastActivity, want)
+	}
+	settings := room.Settings
+	if settings.PasswordHash != "hash" {
+		t.Errorf("password hash = %q, want it moved into the settings", settings.PasswordHash)
+	}
+	if settings.PoolSeed != 9007199254740993 {
+		t.Errorf("pool seed = %d, want 9007199254740993", settings.PoolSeed)
+	}
+	if settings.SquadQuota[domain.PositionLine("DEF")] != 4 || !settings.AutoStart || settings.AutoStartDelay != 15 {
+		t.Errorf("settings = %+v", settings)
+	}
+	alice := room.Users["6f1c7a9e-3d2b-4c5a-8e9f-0a1b2c3d4e5f"]
+	bob := room.Users["0d9e8f7a-6b5c-4d3e-9f2a-1b0c9d8e7f6a"]
+	if alice == nil || bob == nil {
+		t.Fatalf("users = %v", room.Users)
+	}
+	if alice.Username != "alice" || !alice.IsHost || alice.ReconnectToken != "token-a" {
+		t.Errorf("alice = %+v", alice)
+	}
+	if bob.Role != domain.RoleSpectator || !bob.IsCoHost || !bob.Disconnected {
+		t.Errorf("bob = %+v", bob)
+	}
+
+	// Saving writes the current schema, which must read back unchanged.
+	data, err := json.Marshal(newRoomRecord(room))
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+	var saved roomRecord
+	if err := decodeRecord(data, roomMigrations, &saved); err != nil {
+		t.Fatalf("decoding the saved room: %v", err)
+	}
+	again, err := saved.toDomain()
+	if err != nil {
+		t.Fatalf("converting the saved room: %v", err)
+	}
+	if !reflect.DeepEqual(again, room) {
+		t.Errorf("round trip changed the room:\n got %+v\nwant %+v", again, room)
+	}
+}
+
+func TestDecodeLegacyUser(t *testing.T) {
+	var record userRecord
+	if err := decodeRecord([]byte(legacyUser), userMigrations, &record); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	user, err := record.toDomain()
+	if err != nil {
+		t.Fatalf("converting: %v", err)
+	}
+	if user.ID.String() != "6f1c7a9e-3d2b-4c5a-8e9f-0a1b2c3d4e5f" || user.Username != "alice" || user.RoomID != "r1" {
+		t.Errorf("user = %+v", user)
+	}
+	if !user.IsHost || !user.Ready || !user.Disconnected || user.ReconnectToken != "token-a" {
+		t.Errorf("user = %+v", user)
+	}
+
+	record = newUserRecord(user)
+	record.Schema = len(userMigrations)
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+	var saved userRecord
+	if err := decodeRecord(data, userMigrations, &saved); err != nil {
+		t.Fatalf("decoding the saved user: %v", err)
+	}
+	again, err := saved.toDomain()
+	if err != nil {
+		t.Fatalf("converting the saved user: %v", err)
+	}
+	if !reflect.DeepEqual(again, user) {
+		t.Errorf("round trip changed the user:\n got %+v\nwant %+v", again, user)
+	}
+}
+
+func TestDecodeLegacyChatMessage(t *testing.T) {
+	var record chatMessageRecord
+	if err := decodeRecord([]byte(legacyChatMessage), chatMigrations, &record); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	msg := record.toDomain()
+	if msg.ID != 12 || msg.Kind != domain.ChatSystem || msg.Message != "alice bought Messi for 90" {
+		t.Errorf("message = %+v", msg)
+	}
+	if len(msg.Mentions) != 1 || len(msg.Reactions["👍"]) != 1 {
+		t.Errorf("mentions = %v, reactions = %v", msg.Mentions, msg.Reactions)
+	}
+
+	data, err := json.Marshal(newChatMessageRecord(&msg))
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+	var saved chatMessageRecord
+	if err := decodeRecord(data, chatMigrations, &saved); err != nil {
+		t.Fatalf("decoding the saved message: %v", err)
+	}
+	if again := saved.toDomain(); !reflect.DeepEqual(again, msg) {
+		t.Errorf("round trip changed the message:\n got %+v\nwant %+v", again, msg)
+	}
+}
+
+func TestDecodeRecordRejectsNewerSchema(t *testing.T) {
+	var record userRecord
+	data := []byte(`{"schema": 99, "id": "6f1c7a9e-3d2b-4c5a-8e9f-0a1b2c3d4e5f"}`)
+	if err := decodeRecord(data, userMigrations, &record); err == nil {
+		t.Error("decoded a record from a newer schema")
+	}
+}
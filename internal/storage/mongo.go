@@ -29,11 +29,10 @@ func GetMongoClient() (*mongo.Client, error) {
 }
 
 func FetchRandomPlayers(n int) ([]domain.Player, error) {
-	client, err := GetMongoClient()
+	coll, err := playersCollection()
 	if err != nil {
 		return nil, err
 	}
-	coll := client.Database("auction").Collection("players")
 	pipeline := mongo.Pipeline{
 		{{Key: "$sample", Value: bson.D{{Key: "size", Value: n}}}},
 	}
@@ -53,13 +52,12 @@ func FetchRandomPlayers(n int) ([]domain.Player, error) {
 }
 
 func FetchRandomPlayersByPosition(position string, n int) ([]domain.Player, error) {
-	client, err := GetMongoClient()
+	coll, err := playersCollection()
 	if err != nil {
 		return nil, err
 	}
-	coll := client.Database("auction").Collection("players")
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.D{{Key: "Position", Value: position}}}},
+		{{Key: "$match", Value: positionMatch(position)}},
 		{{Key: "$sample", Value: bson.D{{Key: "size", Value: n}}}},
 	}
 	cur, err := coll.Aggregate(context.Background(), pipeline)
@@ -76,3 +74,102 @@ func FetchRandomPlayersByPosition(position string, n int) ([]domain.Player, erro
 	}
 	return players, nil
 }
+
+// overallFilter matches the players eligible for the given role or line
+// whose Overall lies in [minOverall, maxOverall]. An empty position matches
+// all positions.
+func overallFilter(position string, minOverall, maxOverall int) bson.D {
+	filter := bson.D{{Key: "Overall", Value: bson.D{
+		{Key: "$gte", Value: minOverall},
+		{Key: "$lte", Value: maxOverall},
+	}}}
+	if position != "" {
+		filter = bson.D{{Key: "$and", Value: bson.A{filter, positionMatch(position)}}}
+	}
+	return filter
+}
+
+// CountPlayersByOverall counts the players matched by FetchPlayersByOverall.
+func CountPlayersByOverall(position string, minOverall, maxOverall int) (int, error) {
+	coll, err := playersCollection()
+	if err != nil {
+		return 0, err
+	}
+	n, err := coll.CountDocuments(context.Background(), overallFilter(position, minOverall, maxOverall))
+	return int(n), err
+}
+
+// FetchPlayersByOverall returns the players at the given offsets among those
+// eligible for the given role or line whose Overall lies in [minOverall,
+// maxOverall], ordered by _id, so callers can draw from a band reproducibly
+// without loading all of it. Offsets past the end are skipped.
+//
+// Only the band's _ids are read to resolve the offsets; the players
+// themselves are fetched in one query.
+func FetchPlayersByOverall(position string, minOverall, maxOverall int, offsets []int) ([]domain.Player, error) {
+	if len(offsets) == 0 {
+		return nil, nil
+	}
+	coll, err := playersCollection()
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	wanted := make(map[int]bool, len(offsets))
+	last := 0
+	for _, offset := range offsets {
+		wanted[offset] = true
+		last = max(last, offset)
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetProjection(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(last + 1))
+	cur, err := coll.Find(ctx, overallFilter(position, minOverall, maxOverall), opts)
+	if err != nil {
+		return nil, err
+	}
+	var ids bson.A
+	for i := 0; cur.Next(ctx); i++ {
+		if !wanted[i] {
+			continue
+		}
+		var doc struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			cur.Close(ctx)
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	err = cur.Err()
+	cur.Close(ctx)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	cur, err = coll.Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var players []domain.Player
+	if err := cur.All(ctx, &players); err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+// positionMatch matches players whose primary or eligible positions belong to
+// the given role or line, including side-specific aliases.
+func positionMatch(positions ...string) bson.D {
+	var codes []string
+	for _, p := range positions {
+		codes = append(codes, domain.PositionCodes(p)...)
+	}
+	return bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "Position", Value: bson.D{{Key: "$in", Value: codes}}}},
+		bson.D{{Key: "Eligible Positions", Value: bson.D{{Key: "$in", Value: codes}}}},
+	}}}
+}
@@ -0,0 +1,260 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	DefaultPlayerPageSize = 20
+	MaxPlayerPageSize     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PlayerFilter describes a query over the player catalogue. Zero values mean
+// "no constraint"; ranges are inclusive.
+type PlayerFilter struct {
+	Positions     []string `json:"positions,omitempty" query:"position"`
+	Club          string   `json:"club,omitempty" query:"club"`
+	Nationality   string   `json:"nationality,omitempty" query:"nationality"`
+	PreferredFoot string   `json:"preferredFoot,omitempty" query:"preferredFoot"`
+	Name          string   `json:"name,omitempty" query:"name"`
+	MinOverall    int      `json:"minOverall,omitempty" query:"minOverall"`
+	MaxOverall    int      `json:"maxOverall,omitempty" query:"maxOverall"`
+	MinAge        int      `json:"minAge,omitempty" query:"minAge"`
+	MaxAge        int      `json:"maxAge,omitempty" query:"maxAge"`
+	MinValue      int      `json:"minValue,omitempty" query:"minValue"`
+	MaxValue      int      `json:"maxValue,omitempty" query:"maxValue"`
+	SortBy        string   `json:"sortBy,omitempty" query:"sortBy"` // overall, age, value or name
+	Order         string   `json:"order,omitempty" query:"order"`   // asc or desc
+	Limit         int      `json:"limit,omitempty" query:"limit"`
+	Cursor        string   `json:"cursor,omitempty" query:"cursor"`
+}
+
+type PlayerPage struct {
+	Players    []domain.Player `json:"players"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
+
+// playerSortFields maps the public sort keys to the collection field names.
+var playerSortFields = map[string]string{
+	"overall": "Overall",
+	"age":     "Age",
+	"value":   "Value",
+	"name":    "Name",
+}
+
+// playerCursor is the sort value and ID of the last player on a page. Value
+// is an int, or a string when sorting by Name.
+type playerCursor struct {
+	Value interface{} `json:"v"`
+	ID    string      `json:"id"`
+}
+
+func playersCollection() (*mongo.Collection, error) {
+	client, err := GetMongoClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Database("auction").Collection("players"), nil
+}
+
+// EnsurePlayerIndexes creates the indexes backing SearchPlayers.
+func EnsurePlayerIndexes() error {
+	coll, err := playersCollection()
+	if err != nil {
+		return err
+	}
+	_, err = coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "Position", Value: 1}, {Key: "Overall", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "Club", Value: 1}, {Key: "Overall", Value: -1}}},
+		{Keys: bson.D{{Key: "Nationality", Value: 1}, {Key: "Overall", Value: -1}}},
+		{Keys: bson.D{{Key: "Overall", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "Age", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "Value", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "Name", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "Eligible Positions", Value: 1}, {Key: "Overall", Value: -1}}},
+		{Keys: bson.D{{Key: "Preferred Foot", Value: 1}}},
+	})
+	return err
+}
+
+// SearchPlayers returns one page of players matching the filter, ordered by
+// the requested sort key with _id as a tie-breaker so cursors are stable.
+func SearchPlayers(filter PlayerFilter) (*PlayerPage, error) {
+	coll, err := playersCollection()
+	if err != nil {
+		return nil, err
+	}
+	sortField, ok := playerSortFields[filter.SortBy]
+	if !ok {
+		sortField = "Overall"
+	}
+	dir := -1
+	if filter.Order == "asc" || (filter.Order == "" && sortField == "Name") {
+		dir = 1
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultPlayerPageSize
+	}
+	if limit > MaxPlayerPageSize {
+		limit = MaxPlayerPageSize
+	}
+
+	conditions := playerFilterConditions(filter)
+	if filter.Cursor != "" {
+		cond, err := cursorCondition(filter.Cursor, sortField, dir)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+	query := bson.D{}
+	if len(conditions) > 0 {
+		query = bson.D{{Key: "$and", Value: conditions}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: dir}, {Key: "_id", Value: dir}}).
+		SetLimit(int64(limit + 1))
+	cur, err := coll.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+	players := make([]domain.Player, 0, limit+1)
+	if err := cur.All(context.Background(), &players); err != nil {
+		return nil, err
+	}
+
+	page := &PlayerPage{Players: players}
+	if len(players) > limit {
+		page.Players = players[:limit]
+		last := page.Players[limit-1]
+		page.NextCursor = encodePlayerCursor(playerCursor{
+			Value: playerSortValue(last, sortField),
+			ID:    last.ID,
+		})
+	}
+	return page, nil
+}
+
+func playerFilterConditions(filter PlayerFilter) bson.A {
+	conditions := bson.A{}
+	if len(filter.Positions) > 0 {
+		conditions = append(conditions, positionMatch(filter.Positions...))
+	}
+	if filter.Club != "" {
+		conditions = append(conditions, bson.D{{Key: "Club", Value: filter.Club}})
+	}
+	if filter.Nationality != "" {
+		conditions = append(conditions, bson.D{{Key: "Nationality", Value: filter.Nationality}})
+	}
+	if filter.PreferredFoot != "" {
+		conditions = append(conditions, bson.D{{Key: "Preferred Foot", Value: filter.PreferredFoot}})
+	}
+	if filter.Name != "" {
+		conditions = append(conditions, bson.D{{Key: "Name", Value: primitive.Regex{
+			Pattern: regexp.QuoteMeta(filter.Name),
+			Options: "i",
+		}}})
+	}
+	conditions = appendRange(conditions, "Overall", filter.MinOverall, filter.MaxOverall)
+	conditions = appendRange(conditions, "Age", filter.MinAge, filter.MaxAge)
+	conditions = appendRange(conditions, "Value", filter.MinValue, filter.MaxValue)
+	return conditions
+}
+
+func appendRange(conditions bson.A, field string, min, max int) bson.A {
+	r := bson.D{}
+	if min > 0 {
+		r = append(r, bson.E{Key: "$gte", Value: min})
+	}
+	if max > 0 {
+		r = append(r, bson.E{Key: "$lte", Value: max})
+	}
+	if len(r) == 0 {
+		return conditions
+	}
+	return append(conditions, bson.D{{Key: field, Value: r}})
+}
+
+func cursorCondition(raw, sortField string, dir int) (bson.D, error) {
+	c, err := decodePlayerCursor(raw, sortField)
+	if err != nil {
+		return nil, err
+	}
+	id, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	op := "$lt"
+	if dir > 0 {
+		op = "$gt"
+	}
+	return bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: sortField, Value: bson.D{{Key: op, Value: c.Value}}}},
+		bson.D{{Key: sortField, Value: c.Value}, {Key: "_id", Value: bson.D{{Key: op, Value: id}}}},
+	}}}, nil
+}
+
+func playerSortValue(p domain.Player, sortField string) interface{} {
+	switch sortField {
+	case "Age":
+		return p.Age
+	case "Value":
+		return p.Value
+	case "Name":
+		return p.Name
+	default:
+		return p.Overall
+	}
+}
+
+func encodePlayerCursor(c playerCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodePlayerCursor reads a cursor, requiring its value to have the sort
+// field's type. The value goes into a query, so anything else, such as a
+// document of operators, is rejected.
+func decodePlayerCursor(raw, sortField string) (playerCursor, error) {
+	var c playerCursor
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	var encoded struct {
+		Value json.RawMessage `json:"v"`
+		ID    string          `json:"id"`
+	}
+	if err := json.Unmarshal(b, &encoded); err != nil || string(encoded.Value) == "null" {
+		return c, ErrInvalidCursor
+	}
+	c.ID = encoded.ID
+	if sortField == "Name" {
+		var name string
+		err = json.Unmarshal(encoded.Value, &name)
+		c.Value = name
+	} else {
+		var n int
+		err = json.Unmarshal(encoded.Value, &n)
+		c.Value = n
+	}
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,302 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/TouchlineTactics/internal/domain"
+)
+
+// The records below are how rooms, users and chat messages are persisted,
+// kept apart from the domain types so that changing those does not change
+// what is stored. Top-level records carry their schema version; see
+// decodeRecord.
+
+type roomRecord struct {
+	Schema       int                   `json:"schema" bson:"schema"`
+	ID           string                `json:"id" bson:"_id"`
+	HostID       string                `json:"hostId" bson:"hostId"`
+	Users        map[string]userRecord `json:"users" bson:"users"`
+	Settings     settingsRecord        `json:"settings" bson:"settings"`
+	Status       string                `json:"status" bson:"status"`
+	PausedFrom   string                `json:"pausedFrom,omitempty" bson:"pausedFrom,omitempty"`
+	CreatedAt    time.Time             `json:"createdAt" bson:"createdAt"`
+	LastActivity time.Time             `json:"lastActivity" bson:"lastActivity"`
+	Moderation   moderationRecord      `json:"moderation" bson:"moderation"`
+	Version      int64                 `json:"version" bson:"version"`
+}
+
+type settingsRecord struct {
+	PasswordHash       string                 `json:"passwordHash,omitempty" bson:"passwordHash,omitempty"`
+	Private            bool                   `json:"private" bson:"private"`
+	GameMode           string                 `json:"gameMode,omitempty" bson:"gameMode,omitempty"`
+	Timer              int                    `json:"timer,omitempty" bson:"timer,omitempty"`
+	MaxUsers           int                    `json:"maxUsers,omitempty" bson:"maxUsers,omitempty"`
+	MinUsers           int                    `json:"minUsers,omitempty" bson:"minUsers,omitempty"`
+	MaxSpectators      int                    `json:"maxSpectators,omitempty" bson:"maxSpectators,omitempty"`
+	SpectatorChat      bool                   `json:"spectatorChat" bson:"spectatorChat"`
+	PoolProfile        string                 `json:"poolProfile,omitempty" bson:"poolProfile,omitempty"`
+	PoolSeed           int64                  `json:"poolSeed,omitempty" bson:"poolSeed,omitempty"`
+	SquadQuota         map[string]int         `json:"squadQuota,omitempty" bson:"squadQuota,omitempty"`
+	Budget             int                    `json:"budget,omitempty" bson:"budget,omitempty"`
+	AutoStart          bool                   `json:"autoStart" bson:"autoStart"`
+	AutoStartDelay     int                    `json:"autoStartDelay,omitempty" bson:"autoStartDelay,omitempty"`
+	AllowLinks         bool                   `json:"allowLinks" bson:"allowLinks"`
+	DisableAuctionChat bool                   `json:"disableAuctionChat" bson:"disableAuctionChat"`
+	Custom             map[string]interface{} `json:"custom,omitempty" bson:"custom,omitempty"`
+}
+
+// userRecord is stored on its own and within its room's record; only the
+// former sets Schema.
+type userRecord struct {
+	Schema         int    `json:"schema,omitempty" bson:"schema,omitempty"`
+	ID             string `json:"id" bson:"_id"`
+	Username       string `json:"username" bson:"username"`
+	RoomID         string `json:"roomId" bson:"roomId"`
+	Role           string `json:"role,omitempty" bson:"role,omitempty"`
+	IsHost         bool   `json:"isHost" bson:"isHost"`
+	IsCoHost       bool   `json:"isCoHost" bson:"isCoHost"`
+	Ready          bool   `json:"ready" bson:"ready"`
+	ReconnectToken string `json:"reconnectToken,omitempty" bson:"reconnectToken,omitempty"`
+	Disconnected   bool   `json:"disconnected" bson:"disconnected"`
+}
+
+type moderationRecord struct {
+	Bans  map[string]banRecord  `json:"bans,omitempty" bson:"bans,omitempty"`
+	Mutes map[string]time.Time  `json:"mutes,omitempty" bson:"mutes,omitempty"`
+	Log   []moderationLogRecord `json:"log,omitempty" bson:"log,omitempty"`
+}
+
+type banRecord struct {
+	UserID   string    `json:"userId" bson:"userId"`
+	Username string    `json:"username,omitempty" bson:"username,omitempty"`
+	BannedBy string    `json:"bannedBy" bson:"bannedBy"`
+	Reason   string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	At       time.Time `json:"at" bson:"at"`
+}
+
+type moderationLogRecord struct {
+	Action   string     `json:"action" bson:"action"`
+	ActorID  string     `json:"actorId" bson:"actorId"`
+	TargetID string     `json:"targetId" bson:"targetId"`
+	Reason   string     `json:"reason,omitempty" bson:"reason,omitempty"`
+	Until    *time.Time `json:"until,omitempty" bson:"until,omitempty"`
+	At       time.Time  `json:"at" bson:"at"`
+}
+
+type chatMessageRecord struct {
+	Schema    int                 `json:"schema" bson:"schema"`
+	ID        int64               `json:"id" bson:"_id"`
+	Kind      string              `json:"kind,omitempty" bson:"kind,omitempty"`
+	UserID    string              `json:"userId,omitempty" bson:"userId,omitempty"`
+	Username  string              `json:"username,omitempty" bson:"username,omitempty"`
+	Message   string              `json:"message" bson:"message"`
+	Timestamp time.Time           `json:"timestamp" bson:"timestamp"`
+	To        string              `json:"to,omitempty" bson:"to,omitempty"`
+	Mentions  []string            `json:"mentions,omitempty" bson:"mentions,omitempty"`
+	Reactions map[string][]string `json:"reactions,omitempty" bson:"reactions,omitempty"`
+}
+
+// newRoomRecord copies room into a record. Callers must hold at least a
+// read lock on room.Mutex if the room is shared.
+func newRoomRecord(room *domain.Room) roomRecord {
+	r := roomRecord{
+		Schema:       len(roomMigrations),
+		ID:           room.ID,
+		HostID:       room.HostID,
+		Users:        make(map[string]userRecord, len(room.Users)),
+		Settings:     newSettingsRecord(room.Settings),
+		Status:       string(room.Status),
+		PausedFrom:   string(room.PausedFrom),
+		CreatedAt:    room.CreatedAt,
+		LastActivity: room.LastActivity,
+		Moderation:   newModerationRecord(room.Moderation),
+		Version:      room.Version,
+	}
+	for id, u := range room.Users {
+		r.Users[id] = newUserRecord(u)
+	}
+	return r
+}
+
+func (r roomRecord) toDomain() (*domain.Room, error) {
+	room := &domain.Room{
+		ID:           r.ID,
+		HostID:       r.HostID,
+		Users:        make(map[string]*domain.User, len(r.Users)),
+		Settings:     r.Settings.toDomain(),
+		Status:       domain.RoomStatus(r.Status),
+		PausedFrom:   domain.RoomStatus(r.PausedFrom),
+		CreatedAt:    r.CreatedAt,
+		LastActivity: r.LastActivity,
+		Moderation:   r.Moderation.toDomain(),
+		Version:      r.Version,
+	}
+	for id, u := range r.Users {
+		user, err := u.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		room.Users[id] = user
+	}
+	return room, nil
+}
+
+func newSettingsRecord(s domain.RoomSettings) settingsRecord {
+	r := settingsRecord{
+		PasswordHash:       s.PasswordHash,
+		Private:            s.Private,
+		GameMode:           s.GameMode,
+		Timer:              s.Timer,
+		MaxUsers:           s.MaxUsers,
+		MinUsers:           s.MinUsers,
+		MaxSpectators:      s.MaxSpectators,
+		SpectatorChat:      s.SpectatorChat,
+		PoolProfile:        s.PoolProfile,
+		PoolSeed:           s.PoolSeed,
+		Budget:             s.Budget,
+		AutoStart:          s.AutoStart,
+		AutoStartDelay:     s.AutoStartDelay,
+		AllowLinks:         s.AllowLinks,
+		DisableAuctionChat: s.DisableAuctionChat,
+		Custom:             s.Custom,
+	}
+	if s.SquadQuota != nil {
+		r.SquadQuota = make(map[string]int, len(s.SquadQuota))
+		for line, n := range s.SquadQuota {
+			r.SquadQuota[string(line)] = n
+		}
+	}
+	return r
+}
+
+func (r settingsRecord) toDomain() domain.RoomSettings {
+	s := domain.RoomSettings{
+		PasswordHash:       r.PasswordHash,
+		Private:            r.Private,
+		GameMode:           r.GameMode,
+		Timer:              r.Timer,
+		MaxUsers:           r.MaxUsers,
+		MinUsers:           r.MinUsers,
+		MaxSpectators:      r.MaxSpectators,
+		SpectatorChat:      r.SpectatorChat,
+		PoolProfile:        r.PoolProfile,
+		PoolSeed:           r.PoolSeed,
+		Budget:             r.Budget,
+		AutoStart:          r.AutoStart,
+		AutoStartDelay:     r.AutoStartDelay,
+		AllowLinks:         r.AllowLinks,
+		DisableAuctionChat: r.DisableAuctionChat,
+		Custom:             r.Custom,
+	}
+	if r.SquadQuota != nil {
+		s.SquadQuota = make(map[domain.PositionLine]int, len(r.SquadQuota))
+		for line, n := range r.SquadQuota {
+			s.SquadQuota[domain.PositionLine(line)] = n
+		}
+	}
+	return s
+}
+
+func newUserRecord(u *domain.User) userRecord {
+	return userRecord{
+		ID:             u.ID.String(),
+		Username:       u.Username,
+		RoomID:         u.RoomID,
+		Role:           string(u.Role),
+		IsHost:         u.IsHost,
+		IsCoHost:       u.IsCoHost,
+		Ready:          u.Ready,
+		ReconnectToken: u.ReconnectToken,
+		Disconnected:   u.Disconnected,
+	}
+}
+
+func (r userRecord) toDomain() (*domain.User, error) {
+	id, err := uuid.Parse(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.User{
+		ID:             id,
+		Username:       r.Username,
+		RoomID:         r.RoomID,
+		Role:           domain.UserRole(r.Role),
+		IsHost:         r.IsHost,
+		IsCoHost:       r.IsCoHost,
+		Ready:          r.Ready,
+		ReconnectToken: r.ReconnectToken,
+		Disconnected:   r.Disconnected,
+	}, nil
+}
+
+func newModerationRecord(m domain.Moderation) moderationRecord {
+	r := moderationRecord{Mutes: m.Mutes}
+	if m.Bans != nil {
+		r.Bans = make(map[string]banRecord, len(m.Bans))
+		for id, b := range m.Bans {
+			r.Bans[id] = banRecord{UserID: b.UserID, Username: b.Username, BannedBy: b.BannedBy, Reason: b.Reason, At: b.At}
+		}
+	}
+	for _, e := range m.Log {
+		r.Log = append(r.Log, moderationLogRecord{
+			Action:   string(e.Action),
+			ActorID:  e.ActorID,
+			TargetID: e.TargetID,
+			Reason:   e.Reason,
+			Until:    e.Until,
+			At:       e.At,
+		})
+	}
+	return r
+}
+
+func (r moderationRecord) toDomain() domain.Moderation {
+	m := domain.Moderation{Mutes: r.Mutes}
+	if r.Bans != nil {
+		m.Bans = make(map[string]domain.Ban, len(r.Bans))
+		for id, b := range r.Bans {
+			m.Bans[id] = domain.Ban{UserID: b.UserID, Username: b.Username, BannedBy: b.BannedBy, Reason: b.Reason, At: b.At}
+		}
+	}
+	for _, e := range r.Log {
+		m.Log = append(m.Log, domain.ModerationEntry{
+			Action:   domain.ModerationAction(e.Action),
+			ActorID:  e.ActorID,
+			TargetID: e.TargetID,
+			Reason:   e.Reason,
+			Until:    e.Until,
+			At:       e.At,
+		})
+	}
+	return m
+}
+
+func newChatMessageRecord(msg *domain.ChatMessage) chatMessageRecord {
+	return chatMessageRecord{
+		Schema:    len(chatMigrations),
+		ID:        msg.ID,
+		Kind:      string(msg.Kind),
+		UserID:    msg.UserID,
+		Username:  msg.Username,
+		Message:   msg.Message,
+		Timestamp: msg.Timestamp,
+		To:        msg.To,
+		Mentions:  msg.Mentions,
+		Reactions: msg.Reactions,
+	}
+}
+
+func (r chatMessageRecord) toDomain() domain.ChatMessage {
+	return domain.ChatMessage{
+		ID:        r.ID,
+		Kind:      domain.ChatKind(r.Kind),
+		UserID:    r.UserID,
+		Username:  r.Username,
+		Message:   r.Message,
+		Timestamp: r.Timestamp,
+		To:        r.To,
+		Mentions:  r.Mentions,
+		Reactions: r.Reactions,
+	}
+}
@@ -3,6 +3,9 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/yourusername/TouchlineTactics/internal/domain"
@@ -10,7 +13,6 @@ import (
 
 type RedisStore struct {
 	Client *redis.Client
-	Ctx    context.Context
 }
 
 func NewRedisStore(addr, password string, db int) *RedisStore {
@@ -19,90 +21,527 @@ func NewRedisStore(addr, password string, db int) *RedisStore {
 		Password: password,
 		DB:       db,
 	})
-	return &RedisStore{
-		Client: client,
-		Ctx:    context.Background(),
+	return &RedisStore{Client: client}
+}
+
+// storageErr translates a Redis error: a missing key is domain.ErrNotFound
+// and other failures wrap domain.ErrStorage. Context errors pass through.
+func storageErr(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, redis.Nil):
+		return domain.ErrNotFound
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return err
 	}
+	return fmt.Errorf("%w: %w", domain.ErrStorage, err)
 }
 
-// Room operations
-func (s *RedisStore) GetRoom(id string) (*domain.Room, bool) {
-	val, err := s.Client.Get(s.Ctx, "room:"+id).Result()
+// get reads the JSON record at key into v.
+func (s *RedisStore) get(ctx context.Context, key string, v interface{}) error {
+	val, err := s.Client.Get(ctx, key).Bytes()
+	if err != nil {
+		return storageErr(err)
+	}
+	if err := json.Unmarshal(val, v); err != nil {
+		return fmt.Errorf("%w: decoding %s: %w", domain.ErrStorage, key, err)
+	}
+	return nil
+}
+
+// getRecord reads the versioned record at key into v, migrating it from
+// an older schema if need be.
+func (s *RedisStore) getRecord(ctx context.Context, key string, migrations []migration, v interface{}) error {
+	val, err := s.Client.Get(ctx, key).Bytes()
+	if err != nil {
+		return storageErr(err)
+	}
+	if err := decodeRecord(val, migrations, v); err != nil {
+		return fmt.Errorf("%w: decoding %s: %w", domain.ErrStorage, key, err)
+	}
+	return nil
+}
+
+// set writes v as JSON at key.
+func (s *RedisStore) set(ctx context.Context, key string, v interface{}, ttl time.Duration) error {
+	b, err := json.Marshal(v)
 	if err != nil {
-		return nil, false
+		return fmt.Errorf("%w: encoding %s: %w", domain.ErrStorage, key, err)
 	}
-	var room domain.Room
-	if err := json.Unmarshal([]byte(val), &room); err != nil {
-		return nil, false
+	return storageErr(s.Client.Set(ctx, key, b, ttl).Err())
+}
+
+func (s *RedisStore) getRoom(ctx context.Context, key string) (*domain.Room, error) {
+	var record roomRecord
+	if err := s.getRecord(ctx, key, roomMigrations, &record); err != nil {
+		return nil, err
+	}
+	room, err := record.toDomain()
+	if err != nil {
+		return nil, fmt.Errorf("%w: decoding %s: %w", domain.ErrStorage, key, err)
 	}
-	return &room, true
+	return room, nil
+}
+
+// Room operations
+func (s *RedisStore) GetRoom(ctx context.Context, id string) (*domain.Room, error) {
+	return s.getRoom(ctx, "room:"+id)
 }
 
-func (s *RedisStore) SaveRoom(room *domain.Room) {
-	b, _ := json.Marshal(room)
-	s.Client.Set(s.Ctx, "room:"+room.ID, b, 0)
+// SaveRoom writes room and bumps its version, provided the stored copy is
+// still the version room was read at; a missing room counts as version 0.
+// The key is watched so a save from another node in between fails with
+// domain.ErrConflict.
+func (s *RedisStore) SaveRoom(ctx context.Context, room *domain.Room) error {
+	key := "room:" + room.ID
+	record := newRoomRecord(room)
+	record.Version++
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("%w: encoding %s: %w", domain.ErrStorage, key, err)
+	}
+	txf := func(tx *redis.Tx) error {
+		var current roomRecord
+		val, err := tx.Get(ctx, key).Bytes()
+		switch {
+		case errors.Is(err, redis.Nil):
+		case err != nil:
+			return err
+		default:
+			if err := decodeRecord(val, roomMigrations, &current); err != nil {
+				return err
+			}
+		}
+		if current.Version != room.Version {
+			return domain.ErrConflict
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return pipe.Set(ctx, key, b, 0).Err()
+		})
+		return err
+	}
+	switch err := s.Client.Watch(ctx, txf, key); {
+	case err == nil:
+		room.Version = record.Version
+		return nil
+	case errors.Is(err, redis.TxFailedErr), errors.Is(err, domain.ErrConflict):
+		return domain.ErrConflict
+	default:
+		return storageErr(err)
+	}
 }
 
-func (s *RedisStore) DeleteRoom(id string) {
-	s.Client.Del(s.Ctx, "room:"+id)
+func (s *RedisStore) DeleteRoom(ctx context.Context, id string) error {
+	codes, err := s.Client.SMembers(ctx, "invites:"+id).Result()
+	if err != nil {
+		return storageErr(err)
+	}
+	keys := []string{"room:" + id, "pool:" + id, "invites:" + id, "chat:" + id, "chat:" + id + ":seq"}
+	for _, code := range codes {
+		keys = append(keys, "invite:"+code, "invite:"+code+":uses")
+	}
+	// Squads and lineups are kept per manager.
+	for _, pattern := range []string{"team:" + id + ":*", "lineup:" + id + ":*"} {
+		iter := s.Client.Scan(ctx, 0, pattern, 0).Iterator()
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		if err := iter.Err(); err != nil {
+			return storageErr(err)
+		}
+	}
+	return storageErr(s.Client.Del(ctx, keys...).Err())
 }
 
 // User operations
-func (s *RedisStore) GetUser(id string) (*domain.User, bool) {
-	val, err := s.Client.Get(s.Ctx, "user:"+id).Result()
+func (s *RedisStore) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	key := "user:" + id
+	var record userRecord
+	if err := s.getRecord(ctx, key, userMigrations, &record); err != nil {
+		return nil, err
+	}
+	user, err := record.toDomain()
+	if err != nil {
+		return nil, fmt.Errorf("%w: decoding %s: %w", domain.ErrStorage, key, err)
+	}
+	return user, nil
+}
+
+func (s *RedisStore) SaveUser(ctx context.Context, user *domain.User) error {
+	id := user.ID.String()
+	old, err := s.GetUser(ctx, id)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+	case err != nil:
+		return err
+	case old.ReconnectToken != "" && old.ReconnectToken != user.ReconnectToken:
+		if err := s.Client.Del(ctx, "reconnect:"+old.ReconnectToken).Err(); err != nil {
+			return storageErr(err)
+		}
+	}
+	record := newUserRecord(user)
+	record.Schema = len(userMigrations)
+	if err := s.set(ctx, "user:"+id, record, 0); err != nil {
+		return err
+	}
+	if user.ReconnectToken != "" {
+		return storageErr(s.Client.Set(ctx, "reconnect:"+user.ReconnectToken, id, 0).Err())
+	}
+	return nil
+}
+
+func (s *RedisStore) DeleteUser(ctx context.Context, id string) error {
+	user, err := s.GetUser(ctx, id)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	}
+	keys := []string{"user:" + id}
+	if user.ReconnectToken != "" {
+		keys = append(keys, "reconnect:"+user.ReconnectToken)
+	}
+	return storageErr(s.Client.Del(ctx, keys...).Err())
+}
+
+func (s *RedisStore) GetUserByReconnectToken(ctx context.Context, token string) (*domain.User, error) {
+	id, err := s.Client.Get(ctx, "reconnect:"+token).Result()
 	if err != nil {
-		return nil, false
+		return nil, storageErr(err)
 	}
-	var user domain.User
-	if err := json.Unmarshal([]byte(val), &user); err != nil {
-		return nil, false
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	return &user, true
+	if user.ReconnectToken != token {
+		return nil, domain.ErrNotFound
+	}
+	return user, nil
 }
 
-func (s *RedisStore) SaveUser(user *domain.User) {
-	b, _ := json.Marshal(user)
-	s.Client.Set(s.Ctx, "user:"+user.ID.String(), b, 0)
+// Custom player pool operations
+func (s *RedisStore) GetPlayerPool(ctx context.Context, roomID string) ([]domain.Player, error) {
+	var players []domain.Player
+	if err := s.get(ctx, "pool:"+roomID, &players); err != nil {
+		return nil, err
+	}
+	return players, nil
 }
 
-func (s *RedisStore) DeleteUser(id string) {
-	s.Client.Del(s.Ctx, "user:"+id)
+func (s *RedisStore) SavePlayerPool(ctx context.Context, roomID string, players []domain.Player) error {
+	return s.set(ctx, "pool:"+roomID, players, 0)
+}
+
+func (s *RedisStore) DeletePlayerPool(ctx context.Context, roomID string) error {
+	return storageErr(s.Client.Del(ctx, "pool:"+roomID).Err())
 }
 
 // Pub/Sub for distributed events
-func (s *RedisStore) PublishEvent(channel string, data interface{}) {
-	b, _ := json.Marshal(data)
-	s.Client.Publish(s.Ctx, channel, b)
+func (s *RedisStore) PublishEvent(ctx context.Context, channel string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("%w: encoding event: %w", domain.ErrStorage, err)
+	}
+	return storageErr(s.Client.Publish(ctx, channel, b).Err())
 }
 
-func (s *RedisStore) SubscribeEvents(channel string, handler func([]byte)) {
-	pubsub := s.Client.Subscribe(s.Ctx, channel)
+// SubscribeEvents passes handler every message published on a channel
+// matching pattern, with the channel it came on.
+func (s *RedisStore) SubscribeEvents(ctx context.Context, pattern string, handler func(channel string, data []byte)) {
+	pubsub := s.Client.PSubscribe(ctx, pattern)
 	ch := pubsub.Channel()
 	go func() {
 		for msg := range ch {
-			handler([]byte(msg.Payload))
+			handler(msg.Channel, []byte(msg.Payload))
 		}
 	}()
 }
 
-func (s *RedisStore) ListRooms() []*domain.Room {
+// Room event logs. Each room's broadcasts are numbered by
+// "events:"+roomID+":seq" and the latest kept in the list "events:"+roomID.
+func (s *RedisStore) AppendEvent(ctx context.Context, roomID string, keep int, encode func(seq int64) ([]byte, error)) ([]byte, error) {
+	seq, err := s.Client.Incr(ctx, "events:"+roomID+":seq").Result()
+	if err != nil {
+		return nil, storageErr(err)
+	}
+	msg, err := encode(seq)
+	if err != nil {
+		return nil, fmt.Errorf("%w: encoding event: %w", domain.ErrStorage, err)
+	}
+	key := "events:" + roomID
+	pipe := s.Client.TxPipeline()
+	pipe.RPush(ctx, key, msg)
+	pipe.LTrim(ctx, key, int64(-keep), -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, storageErr(err)
+	}
+	return msg, nil
+}
+
+func (s *RedisStore) EventSeq(ctx context.Context, roomID string) (int64, error) {
+	seq, err := s.Client.Get(ctx, "events:"+roomID+":seq").Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return seq, storageErr(err)
+}
+
+func (s *RedisStore) Events(ctx context.Context, roomID string) ([][]byte, error) {
+	vals, err := s.Client.LRange(ctx, "events:"+roomID, 0, -1).Result()
+	if err != nil {
+		return nil, storageErr(err)
+	}
+	msgs := make([][]byte, len(vals))
+	for i, v := range vals {
+		msgs[i] = []byte(v)
+	}
+	return msgs, nil
+}
+
+func (s *RedisStore) ForgetEvents(ctx context.Context, roomID string) error {
+	return storageErr(s.Client.Del(ctx, "events:"+roomID, "events:"+roomID+":seq").Err())
+}
+
+func (s *RedisStore) ListRooms(ctx context.Context) ([]*domain.Room, error) {
 	var rooms []*domain.Room
-	iter := s.Client.Scan(s.Ctx, 0, "room:*", 0).Iterator()
-	for iter.Next(s.Ctx) {
-		val, err := s.Client.Get(s.Ctx, iter.Val()).Result()
+	iter := s.Client.Scan(ctx, 0, "room:*", 0).Iterator()
+	for iter.Next(ctx) {
+		room, err := s.getRoom(ctx, iter.Val())
+		if errors.Is(err, domain.ErrNotFound) {
+			continue // deleted since the scan saw it
+		}
 		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, storageErr(err)
+	}
+	return rooms, nil
+}
+
+func (s *RedisStore) AddPlayerToTeam(ctx context.Context, roomID, userID string, player domain.Player) error {
+	b, err := json.Marshal(player)
+	if err != nil {
+		return fmt.Errorf("%w: encoding player: %w", domain.ErrStorage, err)
+	}
+	key := "team:" + roomID + ":" + userID
+	return storageErr(s.Client.RPush(ctx, key, b).Err())
+}
+
+func (s *RedisStore) GetTeam(ctx context.Context, roomID, userID string) ([]domain.Player, error) {
+	key := "team:" + roomID + ":" + userID
+	vals, err := s.Client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, storageErr(err)
+	}
+	players := make([]domain.Player, 0, len(vals))
+	for _, v := range vals {
+		var p domain.Player
+		if err := json.Unmarshal([]byte(v), &p); err != nil {
+			return nil, fmt.Errorf("%w: decoding %s: %w", domain.ErrStorage, key, err)
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+func (s *RedisStore) GetLineup(ctx context.Context, roomID, userID string) (*domain.Lineup, error) {
+	var lineup domain.Lineup
+	if err := s.get(ctx, "lineup:"+roomID+":"+userID, &lineup); err != nil {
+		return nil, err
+	}
+	return &lineup, nil
+}
+
+func (s *RedisStore) SaveLineup(ctx context.Context, lineup *domain.Lineup) error {
+	return s.set(ctx, "lineup:"+lineup.RoomID+":"+lineup.UserID, lineup, 0)
+}
+
+// Invite operations. Invites expire from Redis with their ExpiresAt; each
+// room keeps a set of its invite codes. Uses are counted apart from the
+// invite, at "invite:"+code+":uses", so that saving an invite never undoes
+// a use counted meanwhile.
+func (s *RedisStore) GetInvite(ctx context.Context, code string) (*domain.Invite, error) {
+	var invite domain.Invite
+	if err := s.get(ctx, "invite:"+code, &invite); err != nil {
+		return nil, err
+	}
+	uses, err := s.Client.Get(ctx, "invite:"+code+":uses").Int()
+	switch {
+	case err == nil:
+		invite.Uses = uses
+	case !errors.Is(err, redis.Nil):
+		return nil, storageErr(err)
+	}
+	return &invite, nil
+}
+
+// inviteTTL is how long an invite is kept: until it expires, or forever.
+func inviteTTL(invite *domain.Invite) time.Duration {
+	if invite.ExpiresAt == nil {
+		return 0
+	}
+	if ttl := time.Until(*invite.ExpiresAt); ttl > 0 {
+		return ttl
+	}
+	return time.Second
+}
+
+func (s *RedisStore) SaveInvite(ctx context.Context, invite *domain.Invite) error {
+	ttl := inviteTTL(invite)
+	if err := s.set(ctx, "invite:"+invite.Code, invite, ttl); err != nil {
+		return err
+	}
+	if ttl > 0 {
+		if err := s.Client.Expire(ctx, "invite:"+invite.Code+":uses", ttl).Err(); err != nil {
+			return storageErr(err)
+		}
+	}
+	return storageErr(s.Client.SAdd(ctx, "invites:"+invite.RoomID, invite.Code).Err())
+}
+
+func (s *RedisStore) AddInviteUse(ctx context.Context, code string, delta int) (int, error) {
+	invite, err := s.GetInvite(ctx, code)
+	if err != nil {
+		return 0, err
+	}
+	key := "invite:" + code + ":uses"
+	// Start the count from the invite's own for invites saved before uses
+	// were counted apart.
+	if err := s.Client.SetNX(ctx, key, invite.Uses, inviteTTL(invite)).Err(); err != nil {
+		return 0, storageErr(err)
+	}
+	uses, err := s.Client.IncrBy(ctx, key, int64(delta)).Result()
+	if err != nil {
+		return 0, storageErr(err)
+	}
+	return int(uses), nil
+}
+
+func (s *RedisStore) DeleteInvite(ctx context.Context, code string) error {
+	invite, err := s.GetInvite(ctx, code)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	}
+	if err := s.Client.SRem(ctx, "invites:"+invite.RoomID, code).Err(); err != nil {
+		return storageErr(err)
+	}
+	return storageErr(s.Client.Del(ctx, "invite:"+code, "invite:"+code+":uses").Err())
+}
+
+func (s *RedisStore) ListInvites(ctx context.Context, roomID string) ([]*domain.Invite, error) {
+	codes, err := s.Client.SMembers(ctx, "invites:"+roomID).Result()
+	if err != nil {
+		return nil, storageErr(err)
+	}
+	var invites []*domain.Invite
+	for _, code := range codes {
+		invite, err := s.GetInvite(ctx, code)
+		if errors.Is(err, domain.ErrNotFound) {
+			s.Client.SRem(ctx, "invites:"+roomID, code) // expired
 			continue
 		}
-		var room domain.Room
-		if err := json.Unmarshal([]byte(val), &room); err == nil {
-			rooms = append(rooms, &room)
+		if err != nil {
+			return nil, err
 		}
+		invites = append(invites, invite)
 	}
-	return rooms
+	return invites, nil
 }
 
-func (s *RedisStore) AddPlayerToTeam(roomID, userID string, player domain.Player) error {
-	b, _ := json.Marshal(player)
-	key := "team:" + roomID + ":" + userID
-	return s.Client.RPush(s.Ctx, key, b).Err()
+// Chat operations. Each room's messages are a list at "chat:"+roomID,
+// trimmed to the latest ChatHistoryLimit, with IDs from "chat:"+roomID+":seq".
+func (s *RedisStore) AppendChatMessage(ctx context.Context, roomID string, msg *domain.ChatMessage) error {
+	id, err := s.Client.Incr(ctx, "chat:"+roomID+":seq").Result()
+	if err != nil {
+		return storageErr(err)
+	}
+	msg.ID = id
+	b, err := json.Marshal(newChatMessageRecord(msg))
+	if err != nil {
+		return fmt.Errorf("%w: encoding message: %w", domain.ErrStorage, err)
+	}
+	key := "chat:" + roomID
+	pipe := s.Client.TxPipeline()
+	pipe.RPush(ctx, key, b)
+	pipe.LTrim(ctx, key, -ChatHistoryLimit, -1)
+	_, err = pipe.Exec(ctx)
+	return storageErr(err)
+}
+
+func (s *RedisStore) GetChatHistory(ctx context.Context, roomID string, cursor domain.ChatCursor, limit int) ([]domain.ChatMessage, bool, error) {
+	vals, err := s.Client.LRange(ctx, "chat:"+roomID, 0, -1).Result()
+	if err != nil {
+		return nil, false, storageErr(err)
+	}
+	history := make([]domain.ChatMessage, 0, len(vals))
+	for _, v := range vals {
+		if msg, err := decodeChatMessage(v); err == nil {
+			history = append(history, msg)
+		}
+	}
+	page, more := pageChat(history, cursor, limit)
+	return page, more, nil
+}
+
+// UpdateChatMessage rewrites one message in place. The list is watched so a
+// concurrent append or trim, which shifts indexes, makes it start over;
+// domain.ErrConflict is returned if it keeps losing that race.
+func (s *RedisStore) UpdateChatMessage(ctx context.Context, roomID string, id int64, update func(*domain.ChatMessage) error) (domain.ChatMessage, error) {
+	key := "chat:" + roomID
+	var updated domain.ChatMessage
+	var rejected error
+	txf := func(tx *redis.Tx) error {
+		vals, err := tx.LRange(ctx, key, 0, -1).Result()
+		if err != nil {
+			return err
+		}
+		for i, v := range vals {
+			msg, err := decodeChatMessage(v)
+			if err != nil || msg.ID != id {
+				continue
+			}
+			if rejected = update(&msg); rejected != nil {
+				return rejected
+			}
+			b, err := json.Marshal(newChatMessageRecord(&msg))
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				return pipe.LSet(ctx, key, int64(i), b).Err()
+			})
+			updated = msg
+			return err
+		}
+		return domain.ErrChatMessageNotFound
+	}
+	for attempt := 0; attempt < chatUpdateAttempts; attempt++ {
+		err := s.Client.Watch(ctx, txf, key)
+		switch {
+		case errors.Is(err, redis.TxFailedErr):
+			continue
+		case err == nil:
+			return updated, nil
+		case err == rejected, err == domain.ErrChatMessageNotFound:
+			return domain.ChatMessage{}, err
+		}
+		return domain.ChatMessage{}, storageErr(err)
+	}
+	return domain.ChatMessage{}, domain.ErrConflict
+}
+
+func decodeChatMessage(v string) (domain.ChatMessage, error) {
+	var record chatMessageRecord
+	if err := decodeRecord([]byte(v), chatMigrations, &record); err != nil {
+		return domain.ChatMessage{}, err
+	}
+	return record.toDomain(), nil
 }
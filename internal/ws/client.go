@@ -1,6 +1,7 @@
 package ws
 
 import (
+	"context"
 	"log"
 
 	"github.com/gorilla/websocket"
@@ -23,7 +24,9 @@ func (c *Client) ReadPump(hub *Hub) {
 }
 
 func (c *Client) ReadPumpWithDispatcher(hub *Hub, dispatcher *room.EventDispatcher) {
+	ctx, cancel := context.WithCancel(context.Background())
 	defer func() {
+		cancel()
 		hub.Unregister <- c
 		c.Conn.Close()
 	}()
@@ -33,7 +36,7 @@ func (c *Client) ReadPumpWithDispatcher(hub *Hub, dispatcher *room.EventDispatch
 			log.Println("read error:", err)
 			break
 		}
-		dispatcher.Dispatch(c, message)
+		dispatcher.Dispatch(ctx, c, message)
 	}
 }
 
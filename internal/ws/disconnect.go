@@ -2,14 +2,10 @@ package ws
 
 import (
 	"github.com/yourusername/TouchlineTactics/internal/app/room"
-	"github.com/yourusername/TouchlineTactics/internal/storage"
 )
 
-func OnDisconnect(store *storage.MemoryStore, handler *room.RoomEventHandler, userID string) {
-	user, ok := store.GetUser(userID)
-	if !ok {
-		return
-	}
-	handler.LeaveRoom(user)
-	store.DeleteUser(userID)
+// OnDisconnect holds the user's seat for the reconnect grace window rather
+// than removing them straight away.
+func OnDisconnect(handler *room.RoomEventHandler, client *Client) {
+	handler.HandleDisconnect(client)
 }
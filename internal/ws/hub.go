@@ -30,9 +30,10 @@ func (h *Hub) Run() {
 			h.Mutex.Unlock()
 		case client := <-h.Unregister:
 			h.Mutex.Lock()
-			if _, ok := h.Clients[client.IDValue]; ok {
+			// A reconnect may already have replaced this client.
+			if current, ok := h.Clients[client.IDValue]; ok && current == client {
 				delete(h.Clients, client.IDValue)
-				close(client.SendChan)
+				client.close()
 			}
 			h.Mutex.Unlock()
 		case message := <-h.Broadcast:
@@ -41,7 +42,7 @@ func (h *Hub) Run() {
 				select {
 				case client.SendChan <- message:
 				default:
-					close(client.SendChan)
+					client.close()
 					delete(h.Clients, client.IDValue)
 				}
 			}
@@ -1,19 +1,85 @@
 package ws
 
 import (
+	"log"
+	"sync"
+
 	"github.com/gorilla/websocket"
+	"github.com/yourusername/TouchlineTactics/internal/app/room"
 )
 
 type Client struct {
 	Conn     *websocket.Conn
 	SendChan chan []byte
 	IDValue  string
+	Addr     string // client IP, for per-IP limits
+
+	// held queues messages while a resumed session replays what it missed.
+	held      [][]byte
+	holding   bool
+	holdMutex sync.Mutex
+	// closed is set once SendChan is closed; later sends are dropped.
+	closed bool
 }
 
+// Send queues msg for the socket. Messages to a closed client, or to one
+// whose buffer is full, are dropped.
 func (c *Client) Send(msg []byte) {
-	c.SendChan <- msg
+	c.holdMutex.Lock()
+	defer c.holdMutex.Unlock()
+	switch {
+	case c.closed:
+	case c.holding:
+		c.held = append(c.held, msg)
+	default:
+		select {
+		case c.SendChan <- msg:
+		default:
+			log.Println("send buffer full, dropping message for", c.IDValue)
+		}
+	}
+}
+
+// close closes SendChan, which stops WritePump. Only the hub calls it.
+func (c *Client) close() {
+	c.holdMutex.Lock()
+	defer c.holdMutex.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.SendChan)
+	}
 }
 
 func (c *Client) ID() string {
 	return c.IDValue
 }
+
+func (c *Client) RemoteAddr() string {
+	return c.Addr
+}
+
+func (c *Client) HoldEvents() {
+	c.holdMutex.Lock()
+	defer c.holdMutex.Unlock()
+	c.holding = true
+}
+
+// Replay sends msgs ahead of the held messages, skipping held room events
+// numbered up to seq since the replay already covers them.
+func (c *Client) Replay(msgs [][]byte, seq int64) {
+	c.holdMutex.Lock()
+	defer c.holdMutex.Unlock()
+	if c.closed {
+		c.held, c.holding = nil, false
+		return
+	}
+	for _, msg := range msgs {
+		c.SendChan <- msg
+	}
+	for _, msg := range c.held {
+		if n := room.MessageSeq(msg); n == 0 || n > seq {
+			c.SendChan <- msg
+		}
+	}
+	c.held, c.holding = nil, false
+}
@@ -0,0 +1,48 @@
+package ws
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientSendAfterClose(t *testing.T) {
+	c := &Client{IDValue: "u1", SendChan: make(chan []byte, 1)}
+	c.close()
+	c.close() // the hub may drop a slow client and then unregister it
+	c.Send([]byte("late"))
+	c.HoldEvents()
+	c.Replay([][]byte{[]byte("missed")}, 0)
+	if _, open := <-c.SendChan; open {
+		t.Error("a message was queued on a closed client")
+	}
+}
+
+func TestClientSendDropsWhenFull(t *testing.T) {
+	c := &Client{IDValue: "u1", SendChan: make(chan []byte, 1)}
+	c.Send([]byte("first"))
+	c.Send([]byte("second"))
+	if got := <-c.SendChan; string(got) != "first" {
+		t.Errorf("got %q, want first", got)
+	}
+	if len(c.SendChan) != 0 {
+		t.Error("a message was queued beyond the buffer")
+	}
+}
+
+func TestClientHoldAndReplay(t *testing.T) {
+	c := &Client{IDValue: "u1", SendChan: make(chan []byte, 4)}
+	c.HoldEvents()
+	c.Send([]byte("held"))
+	if len(c.SendChan) != 0 {
+		t.Fatal("a held message was sent")
+	}
+	c.Replay([][]byte{[]byte("missed")}, 0)
+	c.Send([]byte("live"))
+	var got []string
+	for len(c.SendChan) > 0 {
+		got = append(got, string(<-c.SendChan))
+	}
+	if want := []string{"missed", "held", "live"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+}
@@ -1,7 +1,7 @@
 package ws
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
@@ -29,6 +29,7 @@ func WebSocketHandler(hub *Hub, dispatcher *room.EventDispatcher) fiber.Handler
 				Conn:     conn,
 				SendChan: make(chan []byte, 256),
 				IDValue:  c.Query("userId"),
+				Addr:     c.IP(),
 			}
 			hub.Register <- client
 			go client.WritePump()
@@ -38,8 +39,12 @@ func WebSocketHandler(hub *Hub, dispatcher *room.EventDispatcher) fiber.Handler
 	}
 }
 
-// WebSocketHandlerWithRoomTracking tracks which room a user is in and updates room membership for real-time broadcasting.
-func WebSocketHandlerWithRoomTracking(hub *Hub, dispatcher *room.EventDispatcher, userID string, addClientToRoom func(roomID, userID string, client *Client), removeClientFromAllRooms func(userID string)) fiber.Handler {
+// WebSocketHandlerWithRoomTracking serves a socket whose room membership is
+// tracked for broadcasting. The room handlers subscribe the socket once a
+// join or resume succeeds; removeClientFromAllRooms only removes this
+// client, so a stale socket closing after its user reconnects leaves the new
+// connection registered. onDisconnect is called once the socket has dropped.
+func WebSocketHandlerWithRoomTracking(hub *Hub, dispatcher *room.EventDispatcher, userID string, removeClientFromAllRooms func(userID string, client room.ClientConn), onDisconnect func(client *Client)) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		fasthttpadaptor.NewFastHTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			conn, err := upgrader.Upgrade(w, r, nil)
@@ -50,42 +55,25 @@ func WebSocketHandlerWithRoomTracking(hub *Hub, dispatcher *room.EventDispatcher
 				Conn:     conn,
 				SendChan: make(chan []byte, 256),
 				IDValue:  userID,
+				Addr:     c.IP(),
 			}
 			hub.Register <- client
 			go client.WritePump()
 
-			var currentRoomID string
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 			for {
 				_, message, err := client.Conn.ReadMessage()
 				if err != nil {
+					// Detach the session before the hub closes SendChan so
+					// no handler picks this client up to send to.
+					removeClientFromAllRooms(userID, client)
+					onDisconnect(client)
 					hub.Unregister <- client
-					removeClientFromAllRooms(userID)
 					client.Conn.Close()
 					break
 				}
-				// Intercept joinRoom/createRoom/leaveRoom
-				type incoming struct {
-					Type    string          `json:"type"`
-					Payload json.RawMessage `json:"payload"`
-				}
-				var inc incoming
-				_ = json.Unmarshal(message, &inc)
-				if inc.Type == "joinRoom" || inc.Type == "createRoom" {
-					var payload struct {
-						RoomID string `json:"roomId"`
-					}
-					_ = json.Unmarshal(inc.Payload, &payload)
-					if payload.RoomID != "" && payload.RoomID != currentRoomID {
-						removeClientFromAllRooms(userID)
-						addClientToRoom(payload.RoomID, userID, client)
-						currentRoomID = payload.RoomID
-					}
-				}
-				if inc.Type == "leaveRoom" {
-					removeClientFromAllRooms(userID)
-					currentRoomID = ""
-				}
-				dispatcher.Dispatch(client, message)
+				dispatcher.Dispatch(ctx, client, message)
 			}
 		})(c.Context())
 		return nil